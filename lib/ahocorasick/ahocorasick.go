@@ -0,0 +1,152 @@
+package ahocorasick
+
+import "slices"
+
+// Match is an occurrence of Patterns()[Pattern] at the byte offsets [Start, End) of the scanned text
+type Match struct {
+	Pattern int
+	Start   int
+	End     int
+}
+
+type node struct {
+	children map[byte]int
+	// fail is the node for the longest proper suffix of this node's prefix that is also in the trie
+	fail int
+	// outputs are the patterns ending at this node, including those ending at any fail node
+	outputs []int
+}
+
+// Matcher finds every occurrence of a fixed set of patterns in one pass over the text,
+// including occurrences that overlap (e.g. "seven" and "nine" in "sevenine")
+type Matcher struct {
+	patterns []string
+	nodes    []node
+	longest  int
+}
+
+// New builds a Matcher for patterns. Empty patterns never match
+func New(patterns ...string) *Matcher {
+	m := &Matcher{
+		patterns: slices.Clone(patterns),
+		nodes:    []node{{children: map[byte]int{}}},
+	}
+
+	for i, pattern := range patterns {
+		if pattern == "" {
+			continue
+		}
+		m.longest = max(m.longest, len(pattern))
+		current := 0
+		for j := 0; j < len(pattern); j++ {
+			next, ok := m.nodes[current].children[pattern[j]]
+			if !ok {
+				next = len(m.nodes)
+				m.nodes = append(m.nodes, node{children: map[byte]int{}})
+				m.nodes[current].children[pattern[j]] = next
+			}
+			current = next
+		}
+		m.nodes[current].outputs = append(m.nodes[current].outputs, i)
+	}
+
+	// Breadth first, so every node's fail node is complete before its children need it
+	queue := []int{}
+	for _, child := range m.nodes[0].children {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for b, child := range m.nodes[current].children {
+			fail := m.nodes[current].fail
+			for fail != 0 {
+				if _, ok := m.nodes[fail].children[b]; ok {
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+			if next, ok := m.nodes[fail].children[b]; ok && next != child {
+				fail = next
+			} else {
+				fail = 0
+			}
+			m.nodes[child].fail = fail
+			m.nodes[child].outputs = append(m.nodes[child].outputs, m.nodes[fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+
+	return m
+}
+
+// Patterns returns the patterns the Matcher was built with, indexed by Match.Pattern
+func (m *Matcher) Patterns() []string {
+	return m.patterns
+}
+
+// Scan calls yield with each match in text in the order they end, until yield returns false.
+// Matches ending at the same byte are reported longest first
+func (m *Matcher) Scan(text string, yield func(Match) bool) {
+	current := 0
+	for i := 0; i < len(text); i++ {
+		b := text[i]
+		for current != 0 {
+			if _, ok := m.nodes[current].children[b]; ok {
+				break
+			}
+			current = m.nodes[current].fail
+		}
+		current = m.nodes[current].children[b] // 0 if not found
+		for _, pattern := range m.nodes[current].outputs {
+			if !yield(Match{Pattern: pattern, Start: i + 1 - len(m.patterns[pattern]), End: i + 1}) {
+				return
+			}
+		}
+	}
+}
+
+// FindAll returns every match in text ordered by Start, then End
+func (m *Matcher) FindAll(text string) []Match {
+	matches := []Match{}
+	m.Scan(text, func(match Match) bool {
+		matches = append(matches, match)
+		return true
+	})
+	slices.SortStableFunc(matches, func(a, b Match) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return a.End - b.End
+	})
+	return matches
+}
+
+// First returns the match that starts earliest in text, the shortest if several start together
+func (m *Matcher) First(text string) (Match, bool) {
+	var first Match
+	found := false
+	m.Scan(text, func(match Match) bool {
+		if !found || match.Start < first.Start || (match.Start == first.Start && match.End < first.End) {
+			first = match
+			found = true
+		}
+		// nothing ending past the longest pattern from here can start earlier
+		return match.End-m.longest < first.Start
+	})
+	return first, found
+}
+
+// Last returns the match that starts latest in text, the longest if several start together
+func (m *Matcher) Last(text string) (Match, bool) {
+	var last Match
+	found := false
+	m.Scan(text, func(match Match) bool {
+		if !found || match.Start > last.Start || (match.Start == last.Start && match.End > last.End) {
+			last = match
+			found = true
+		}
+		return true
+	})
+	return last, found
+}
@@ -0,0 +1,79 @@
+package ahocorasick
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	var testCases = []struct {
+		patterns []string
+		text     string
+		want     []Match
+	}{
+		// overlapping
+		{patterns: []string{"seven", "nine"}, text: "sevenine", want: []Match{{0, 0, 5}, {1, 4, 8}}},
+		// suffix of another pattern
+		{patterns: []string{"he", "she", "his", "hers"}, text: "ushers", want: []Match{{1, 1, 4}, {0, 2, 4}, {3, 2, 6}}},
+		// repeated
+		{patterns: []string{"aa"}, text: "aaaa", want: []Match{{0, 0, 2}, {0, 1, 3}, {0, 2, 4}}},
+		{patterns: []string{"one", "two"}, text: "abc", want: []Match{}},
+		// empty patterns never match
+		{patterns: []string{"", "b"}, text: "abc", want: []Match{{1, 1, 2}}},
+	}
+	for _, testCase := range testCases {
+		result := New(testCase.patterns...).FindAll(testCase.text)
+		if !slices.Equal(result, testCase.want) {
+			t.Errorf("%s: expected %v, got %v", testCase.text, testCase.want, result)
+		}
+	}
+}
+
+// naive finds every match by checking each pattern at each offset
+func naive(patterns []string, text string) []Match {
+	matches := []Match{}
+	for start := range text {
+		for end := start + 1; end <= len(text); end++ {
+			for i, pattern := range patterns {
+				if text[start:end] == pattern {
+					matches = append(matches, Match{i, start, end})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func TestFindAllAgainstNaive(t *testing.T) {
+	patterns := []string{"1", "one", "two", "three", "eight", "nine", "ne", "e"}
+	for _, text := range []string{
+		"eightwothree",
+		"xtwone3four",
+		"oneightwoneine",
+		strings.Repeat("threeight", 5),
+	} {
+		result := New(patterns...).FindAll(text)
+		want := naive(patterns, text)
+		if !slices.Equal(result, want) {
+			t.Errorf("%s: expected %v, got %v", text, want, result)
+		}
+	}
+}
+
+func TestFirstLast(t *testing.T) {
+	m := New("one", "two", "eight", "1", "2")
+
+	first, ok := m.First("xtwone3eightwo")
+	if want := (Match{1, 1, 4}); !ok || first != want {
+		t.Fatalf("expected %v, got %v (found %v)", want, first, ok)
+	}
+	last, ok := m.Last("xtwone3eightwo")
+	if want := (Match{1, 11, 14}); !ok || last != want {
+		t.Fatalf("expected %v, got %v (found %v)", want, last, ok)
+	}
+
+	if match, ok := m.First("abc"); ok {
+		t.Fatalf("expected no match, got %v", match)
+	}
+}
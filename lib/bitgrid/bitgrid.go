@@ -0,0 +1,200 @@
+package bitgrid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+)
+
+// Grid is a fixed size grid of booleans packed one bit per cell.
+// Each row starts on a fresh uint64 so rows can be shifted and compared word by word
+type Grid struct {
+	rows    int
+	columns int
+	stride  int
+	words   []uint64
+}
+
+func New(rows, columns int) *Grid {
+	if rows < 0 || columns < 0 {
+		panic(fmt.Sprintf("invalid grid size %dx%d", rows, columns))
+	}
+	stride := (columns + 63) / 64
+	return &Grid{rows: rows, columns: columns, stride: stride, words: make([]uint64, rows*stride)}
+}
+
+func (g *Grid) Rows() int {
+	return g.rows
+}
+
+func (g *Grid) Columns() int {
+	return g.columns
+}
+
+func (g *Grid) InBounds(row, column int) bool {
+	return row >= 0 && column >= 0 && row < g.rows && column < g.columns
+}
+
+func (g *Grid) index(row, column int) (int, uint64) {
+	if !g.InBounds(row, column) {
+		panic(fmt.Sprintf("(%d, %d) out of bounds for %dx%d grid", row, column, g.rows, g.columns))
+	}
+	return row*g.stride + column/64, 1 << (column % 64)
+}
+
+func (g *Grid) Get(row, column int) bool {
+	i, bit := g.index(row, column)
+	return g.words[i]&bit != 0
+}
+
+func (g *Grid) Set(row, column int) {
+	i, bit := g.index(row, column)
+	g.words[i] |= bit
+}
+
+func (g *Grid) Clear(row, column int) {
+	i, bit := g.index(row, column)
+	g.words[i] &^= bit
+}
+
+// Count returns the number of set cells
+func (g *Grid) Count() int {
+	count := 0
+	for _, w := range g.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// CountRow returns the number of set cells in row
+func (g *Grid) CountRow(row int) int {
+	count := 0
+	for _, w := range g.words[row*g.stride : (row+1)*g.stride] {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+func (g *Grid) Reset() {
+	clear(g.words)
+}
+
+func (g *Grid) Clone() *Grid {
+	clone := *g
+	clone.words = make([]uint64, len(g.words))
+	copy(clone.words, g.words)
+	return &clone
+}
+
+func (g *Grid) Equal(other *Grid) bool {
+	if g.rows != other.rows || g.columns != other.columns {
+		return false
+	}
+	for i, w := range g.words {
+		if other.words[i] != w {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Grid) checkSize(other *Grid) {
+	if g.rows != other.rows || g.columns != other.columns {
+		panic(fmt.Sprintf("mismatched grid sizes %dx%d and %dx%d", g.rows, g.columns, other.rows, other.columns))
+	}
+}
+
+// Or sets every cell that is set in other
+func (g *Grid) Or(other *Grid) {
+	g.checkSize(other)
+	for i, w := range other.words {
+		g.words[i] |= w
+	}
+}
+
+// And clears every cell that is not set in other
+func (g *Grid) And(other *Grid) {
+	g.checkSize(other)
+	for i, w := range other.words {
+		g.words[i] &= w
+	}
+}
+
+// ShiftRows moves every cell n rows down (or up, for negative n).
+// Cells shifted off the edge are lost and vacated rows are cleared
+func (g *Grid) ShiftRows(n int) {
+	if n >= g.rows || -n >= g.rows {
+		g.Reset()
+		return
+	}
+	offset := n * g.stride
+	if n > 0 {
+		copy(g.words[offset:], g.words[:len(g.words)-offset])
+		clear(g.words[:offset])
+	} else if n < 0 {
+		copy(g.words, g.words[-offset:])
+		clear(g.words[len(g.words)+offset:])
+	}
+}
+
+// ShiftColumns moves every cell n columns right (or left, for negative n).
+// Cells shifted off the edge are lost and vacated columns are cleared
+func (g *Grid) ShiftColumns(n int) {
+	if n >= g.columns || -n >= g.columns {
+		g.Reset()
+		return
+	}
+	wordShift, bitShift := abs(n)/64, uint(abs(n)%64)
+	for row := 0; row < g.rows; row++ {
+		words := g.words[row*g.stride : (row+1)*g.stride]
+		if n > 0 {
+			// towards higher columns, i.e. higher bits
+			for i := len(words) - 1; i >= 0; i-- {
+				var w uint64
+				if j := i - wordShift; j >= 0 {
+					w = words[j] << bitShift
+					if bitShift > 0 && j > 0 {
+						w |= words[j-1] >> (64 - bitShift)
+					}
+				}
+				words[i] = w
+			}
+		} else if n < 0 {
+			for i := range words {
+				var w uint64
+				if j := i + wordShift; j < len(words) {
+					w = words[j] >> bitShift
+					if bitShift > 0 && j+1 < len(words) {
+						w |= words[j+1] << (64 - bitShift)
+					}
+				}
+				words[i] = w
+			}
+		}
+		// drop anything shifted past the last column
+		if rem := g.columns % 64; rem != 0 {
+			words[len(words)-1] &= (1 << rem) - 1
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Hash returns an FNV-64a hash of the grid's size and contents
+func (g *Grid) Hash() uint64 {
+	hash := fnv.New64a()
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(g.rows)<<32|uint64(g.columns))
+	hash.Write(buf)
+	for _, w := range g.words {
+		binary.LittleEndian.PutUint64(buf, w)
+		hash.Write(buf)
+	}
+	return hash.Sum64()
+}
@@ -0,0 +1,130 @@
+package bitgrid
+
+import (
+	"strings"
+	"testing"
+)
+
+func fromStrings(lines []string) *Grid {
+	g := New(len(lines), len(lines[0]))
+	for row, line := range lines {
+		for column, c := range line {
+			if c == '#' {
+				g.Set(row, column)
+			}
+		}
+	}
+	return g
+}
+
+func toString(g *Grid) string {
+	var sb strings.Builder
+	for row := 0; row < g.Rows(); row++ {
+		for column := 0; column < g.Columns(); column++ {
+			if g.Get(row, column) {
+				sb.WriteByte('#')
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+func TestGrid(t *testing.T) {
+	g := fromStrings([]string{
+		"#..#",
+		".#..",
+		"...#",
+	})
+	if g.Count() != 4 || g.CountRow(0) != 2 {
+		t.Fatalf("expected 4 set cells with 2 in row 0, got %d and %d", g.Count(), g.CountRow(0))
+	}
+
+	g.Clear(0, 0)
+	if g.Get(0, 0) || g.Count() != 3 {
+		t.Fatalf("expected (0, 0) to be cleared")
+	}
+
+	clone := g.Clone()
+	if !clone.Equal(g) || clone.Hash() != g.Hash() {
+		t.Fatalf("expected clone to equal the original")
+	}
+	clone.Set(2, 0)
+	if clone.Equal(g) || clone.Hash() == g.Hash() {
+		t.Fatalf("expected modified clone to differ from the original")
+	}
+}
+
+func TestShift(t *testing.T) {
+	g := fromStrings([]string{
+		"#..#",
+		".#..",
+		"...#",
+	})
+
+	g.ShiftRows(1)
+	if got, want := toString(g), "....\n#..#\n.#..\n"; got != want {
+		t.Errorf("ShiftRows(1): expected\n%s\ngot\n%s", want, got)
+	}
+	g.ShiftColumns(1)
+	if got, want := toString(g), "....\n.#..\n..#.\n"; got != want {
+		t.Errorf("ShiftColumns(1): expected\n%s\ngot\n%s", want, got)
+	}
+	g.ShiftColumns(-2)
+	if got, want := toString(g), "....\n....\n#...\n"; got != want {
+		t.Errorf("ShiftColumns(-2): expected\n%s\ngot\n%s", want, got)
+	}
+	g.ShiftRows(-2)
+	if got, want := toString(g), "#...\n....\n....\n"; got != want {
+		t.Errorf("ShiftRows(-2): expected\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestShiftColumnsAcrossWords(t *testing.T) {
+	g := New(1, 130)
+	g.Set(0, 60)
+	g.ShiftColumns(69)
+	if !g.Get(0, 129) || g.Count() != 1 {
+		t.Fatalf("expected only column 129 to be set")
+	}
+	g.ShiftColumns(1)
+	if g.Count() != 0 {
+		t.Fatalf("expected the cell to be shifted off the edge")
+	}
+
+	g.Set(0, 128)
+	g.ShiftColumns(-65)
+	if !g.Get(0, 63) || g.Count() != 1 {
+		t.Fatalf("expected only column 63 to be set")
+	}
+}
+
+func TestLayeredGrid(t *testing.T) {
+	g := NewLayered(3, 7)
+	if !g.Add(1, 2, East) {
+		t.Fatalf("expected first Add to report a change")
+	}
+	if g.Add(1, 2, East) {
+		t.Fatalf("expected repeated Add to report no change")
+	}
+	g.Add(1, 2, North)
+	g.Add(2, 6, West|South)
+
+	if !g.Has(1, 2, East|North) || g.Has(1, 2, South) {
+		t.Fatalf("expected (1, 2) to have exactly North and East, got %04b", g.Mask(1, 2))
+	}
+	if g.Count() != 2 || g.CountDirections() != 4 {
+		t.Fatalf("expected 2 cells and 4 directions, got %d and %d", g.Count(), g.CountDirections())
+	}
+	if flat := g.Flatten(); flat.Count() != 2 || !flat.Get(2, 6) {
+		t.Fatalf("expected flattened grid to have (1, 2) and (2, 6)")
+	}
+
+	before := g.Hash()
+	g.Remove(2, 6, South)
+	if g.Mask(2, 6) != West || g.Hash() == before {
+		t.Fatalf("expected only West left at (2, 6)")
+	}
+}
@@ -0,0 +1,136 @@
+package bitgrid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+)
+
+// Direction is one bit of a LayeredGrid cell's 4-bit mask
+type Direction uint8
+
+const (
+	North Direction = 1 << iota
+	East
+	South
+	West
+)
+
+const cellsPerWord = 16
+
+// LayeredGrid stores a 4-bit mask per cell, one bit per Direction,
+// e.g. to record which way a beam has passed through each cell
+type LayeredGrid struct {
+	rows    int
+	columns int
+	words   []uint64
+}
+
+func NewLayered(rows, columns int) *LayeredGrid {
+	if rows < 0 || columns < 0 {
+		panic(fmt.Sprintf("invalid grid size %dx%d", rows, columns))
+	}
+	cells := rows * columns
+	return &LayeredGrid{rows: rows, columns: columns, words: make([]uint64, (cells+cellsPerWord-1)/cellsPerWord)}
+}
+
+func (g *LayeredGrid) Rows() int {
+	return g.rows
+}
+
+func (g *LayeredGrid) Columns() int {
+	return g.columns
+}
+
+func (g *LayeredGrid) InBounds(row, column int) bool {
+	return row >= 0 && column >= 0 && row < g.rows && column < g.columns
+}
+
+func (g *LayeredGrid) index(row, column int) (int, uint) {
+	if !g.InBounds(row, column) {
+		panic(fmt.Sprintf("(%d, %d) out of bounds for %dx%d grid", row, column, g.rows, g.columns))
+	}
+	cell := row*g.columns + column
+	return cell / cellsPerWord, uint(cell%cellsPerWord) * 4
+}
+
+// Mask returns every Direction set on the cell
+func (g *LayeredGrid) Mask(row, column int) Direction {
+	i, shift := g.index(row, column)
+	return Direction(g.words[i]>>shift) & 0xF
+}
+
+// Has reports whether every Direction in d is set on the cell
+func (g *LayeredGrid) Has(row, column int, d Direction) bool {
+	return g.Mask(row, column)&d == d
+}
+
+// Any reports whether the cell has any Direction set
+func (g *LayeredGrid) Any(row, column int) bool {
+	return g.Mask(row, column) != 0
+}
+
+// Add sets d on the cell, reporting whether that changed anything
+func (g *LayeredGrid) Add(row, column int, d Direction) bool {
+	i, shift := g.index(row, column)
+	before := g.words[i]
+	g.words[i] |= uint64(d&0xF) << shift
+	return g.words[i] != before
+}
+
+func (g *LayeredGrid) Remove(row, column int, d Direction) {
+	i, shift := g.index(row, column)
+	g.words[i] &^= uint64(d&0xF) << shift
+}
+
+// Count returns the number of cells with any Direction set
+func (g *LayeredGrid) Count() int {
+	count := 0
+	for _, w := range g.words {
+		// fold each nibble down onto its lowest bit
+		w |= w >> 1
+		w |= w >> 2
+		count += bits.OnesCount64(w & 0x1111111111111111)
+	}
+	return count
+}
+
+// CountDirections returns the total number of Directions set across all cells
+func (g *LayeredGrid) CountDirections() int {
+	count := 0
+	for _, w := range g.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Flatten returns a Grid with every cell set that has any Direction set
+func (g *LayeredGrid) Flatten() *Grid {
+	flat := New(g.rows, g.columns)
+	for row := 0; row < g.rows; row++ {
+		for column := 0; column < g.columns; column++ {
+			if g.Any(row, column) {
+				flat.Set(row, column)
+			}
+		}
+	}
+	return flat
+}
+
+func (g *LayeredGrid) Reset() {
+	clear(g.words)
+}
+
+// Hash returns an FNV-64a hash of the grid's size and contents
+func (g *LayeredGrid) Hash() uint64 {
+	hash := fnv.New64a()
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, uint64(g.rows)<<32|uint64(g.columns))
+	hash.Write(buf)
+	for _, w := range g.words {
+		binary.LittleEndian.PutUint64(buf, w)
+		hash.Write(buf)
+	}
+	return hash.Sum64()
+}
@@ -0,0 +1,143 @@
+package lib
+
+// Seq and Seq2 are push iterators, in the same shape as the standard library's iter package:
+// the iterator calls yield for each element, stopping early if yield returns false
+type Seq[T any] func(yield func(T) bool)
+
+type Seq2[K, V any] func(yield func(K, V) bool)
+
+// Pairs yields every unordered pair of elements, in order of first appearance
+func Pairs[T any](elements []T) Seq2[T, T] {
+	return func(yield func(T, T) bool) {
+		for i, first := range elements {
+			for j := i + 1; j < len(elements); j++ {
+				if !yield(first, elements[j]) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// The slices yielded below are reused between iterations to avoid allocating;
+// use slices.Clone to keep one beyond the call to yield
+
+// Combinations yields every k-element subset of elements in lexicographic order of index
+func Combinations[T any](elements []T, k int) Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(elements)
+		if k < 0 || k > n {
+			return
+		}
+		indices := make([]int, k)
+		for i := range indices {
+			indices[i] = i
+		}
+		combination := make([]T, k)
+		for {
+			for i, index := range indices {
+				combination[i] = elements[index]
+			}
+			if !yield(combination) {
+				return
+			}
+
+			// Advance the rightmost index that still has room to move
+			i := k - 1
+			for i >= 0 && indices[i] == n-k+i {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+			for j := i + 1; j < k; j++ {
+				indices[j] = indices[j-1] + 1
+			}
+		}
+	}
+}
+
+// Permutations yields every ordering of elements in lexicographic order of index
+func Permutations[T any](elements []T) Seq[[]T] {
+	return func(yield func([]T) bool) {
+		n := len(elements)
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		permutation := make([]T, n)
+		for {
+			for i, index := range indices {
+				permutation[i] = elements[index]
+			}
+			if !yield(permutation) {
+				return
+			}
+
+			// Next lexicographic permutation of indices
+			i := n - 2
+			for i >= 0 && indices[i] >= indices[i+1] {
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			j := n - 1
+			for indices[j] <= indices[i] {
+				j--
+			}
+			indices[i], indices[j] = indices[j], indices[i]
+			for l, r := i+1, n-1; l < r; l, r = l+1, r-1 {
+				indices[l], indices[r] = indices[r], indices[l]
+			}
+		}
+	}
+}
+
+// Product yields the cartesian product of sets, varying the last set fastest
+func Product[T any](sets ...[]T) Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for _, set := range sets {
+			if len(set) == 0 {
+				return
+			}
+		}
+		indices := make([]int, len(sets))
+		product := make([]T, len(sets))
+		for {
+			for i, index := range indices {
+				product[i] = sets[i][index]
+			}
+			if !yield(product) {
+				return
+			}
+
+			i := len(sets) - 1
+			for i >= 0 && indices[i] == len(sets[i])-1 {
+				indices[i] = 0
+				i--
+			}
+			if i < 0 {
+				return
+			}
+			indices[i]++
+		}
+	}
+}
+
+// PowerSet yields every subset of elements, from the empty set upwards in size
+func PowerSet[T any](elements []T) Seq[[]T] {
+	return func(yield func([]T) bool) {
+		for k := 0; k <= len(elements); k++ {
+			more := true
+			Combinations(elements, k)(func(subset []T) bool {
+				more = yield(subset)
+				return more
+			})
+			if !more {
+				return
+			}
+		}
+	}
+}
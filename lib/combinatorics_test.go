@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func collect[T any](seq Seq[[]T]) string {
+	var results []string
+	seq(func(values []T) bool {
+		results = append(results, fmt.Sprint(values))
+		return true
+	})
+	return strings.Join(results, " ")
+}
+
+func TestPairs(t *testing.T) {
+	var pairs []string
+	Pairs([]int{1, 2, 3})(func(a, b int) bool {
+		pairs = append(pairs, fmt.Sprint(a, b))
+		return true
+	})
+	if got := strings.Join(pairs, ","); got != "1 2,1 3,2 3" {
+		t.Fatalf("expected 1 2,1 3,2 3, got %s", got)
+	}
+}
+
+func TestCombinatorics(t *testing.T) {
+	var testCases = []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "combinations", got: collect(Combinations([]int{1, 2, 3, 4}, 2)), want: "[1 2] [1 3] [1 4] [2 3] [2 4] [3 4]"},
+		{name: "combinations k=0", got: collect(Combinations([]int{1, 2}, 0)), want: "[]"},
+		{name: "combinations k>n", got: collect(Combinations([]int{1, 2}, 3)), want: ""},
+		{name: "permutations", got: collect(Permutations([]string{"a", "b", "c"})), want: "[a b c] [a c b] [b a c] [b c a] [c a b] [c b a]"},
+		{name: "product", got: collect(Product([]int{1, 2}, []int{3}, []int{4, 5})), want: "[1 3 4] [1 3 5] [2 3 4] [2 3 5]"},
+		{name: "product with empty set", got: collect(Product([]int{1, 2}, []int{})), want: ""},
+		{name: "power set", got: collect(PowerSet([]int{1, 2, 3})), want: "[] [1] [2] [3] [1 2] [1 3] [2 3] [1 2 3]"},
+	}
+	for _, testCase := range testCases {
+		if testCase.got != testCase.want {
+			t.Errorf("%s: expected %s, got %s", testCase.name, testCase.want, testCase.got)
+		}
+	}
+}
+
+func TestEarlyStop(t *testing.T) {
+	var seen [][]int
+	PowerSet([]int{1, 2, 3})(func(subset []int) bool {
+		seen = append(seen, slices.Clone(subset))
+		return len(subset) < 2
+	})
+	if len(seen) != 5 {
+		t.Fatalf("expected iteration to stop at the first pair, got %v", seen)
+	}
+}
@@ -0,0 +1,129 @@
+package geom
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Point is a lattice point. Y grows downwards when X and Y are a grid's column and row,
+// which flips the sign of DoubleArea but nothing else
+type Point struct {
+	X int64
+	Y int64
+}
+
+var (
+	Up    = Point{X: 0, Y: -1}
+	Down  = Point{X: 0, Y: 1}
+	Left  = Point{X: -1, Y: 0}
+	Right = Point{X: 1, Y: 0}
+)
+
+// ParseDirection accepts U/D/L/R and the compass directions N/S/W/E
+func ParseDirection(d byte) (Point, error) {
+	switch d {
+	case 'U', 'N':
+		return Up, nil
+	case 'D', 'S':
+		return Down, nil
+	case 'L', 'W':
+		return Left, nil
+	case 'R', 'E':
+		return Right, nil
+	default:
+		return Point{}, fmt.Errorf("unexpected direction %q", d)
+	}
+}
+
+// Move is a step of Length in Direction, as in a dig plan
+type Move struct {
+	Direction Point
+	Length    int64
+}
+
+// Trace follows moves from start, returning the vertex reached after each move.
+// For a closed path the last vertex is start again, which is harmless to every function here
+func Trace(start Point, moves []Move) []Point {
+	vertices := make([]Point, 0, len(moves)+1)
+	vertices = append(vertices, start)
+	current := start
+	for _, move := range moves {
+		current = Point{
+			X: current.X + move.Direction.X*move.Length,
+			Y: current.Y + move.Direction.Y*move.Length,
+		}
+		vertices = append(vertices, current)
+	}
+	return vertices
+}
+
+// DoubleArea returns twice the signed area of the polygon with the given vertices
+// in order, by the shoelace formula. It is positive for anticlockwise vertices when
+// Y points up. Twice the area of a lattice polygon is always an integer
+func DoubleArea(vertices []Point) int64 {
+	var sum int64
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		sum += a.X*b.Y - b.X*a.Y
+	}
+	return sum
+}
+
+// BigDoubleArea is DoubleArea for polygons whose coordinates are too large to multiply in an int64
+func BigDoubleArea(vertices []Point) *big.Int {
+	sum := new(big.Int)
+	term := new(big.Int)
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		term.Mul(big.NewInt(a.X), big.NewInt(b.Y))
+		sum.Add(sum, term)
+		term.Mul(big.NewInt(b.X), big.NewInt(a.Y))
+		sum.Sub(sum, term)
+	}
+	return sum
+}
+
+func abs(a int64) int64 {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// BoundaryPoints returns the number of lattice points on the edges of the polygon,
+// which for a path of unit steps (or axis-aligned moves) is its perimeter
+func BoundaryPoints(vertices []Point) int64 {
+	var total int64
+	for i, a := range vertices {
+		b := vertices[(i+1)%len(vertices)]
+		total += gcd(abs(b.X-a.X), abs(b.Y-a.Y))
+	}
+	return total
+}
+
+// InteriorPoints returns the number of lattice points strictly inside the polygon,
+// by Pick's theorem: A = I + B/2 - 1
+func InteriorPoints(vertices []Point) int64 {
+	return (abs(DoubleArea(vertices)) - BoundaryPoints(vertices) + 2) / 2
+}
+
+// BigInteriorPoints is InteriorPoints for polygons too large for DoubleArea
+func BigInteriorPoints(vertices []Point) *big.Int {
+	interior := new(big.Int).Abs(BigDoubleArea(vertices))
+	interior.Sub(interior, big.NewInt(BoundaryPoints(vertices)))
+	interior.Add(interior, big.NewInt(2))
+	return interior.Quo(interior, big.NewInt(2))
+}
+
+// EnclosedPoints returns the number of lattice points inside or on the polygon,
+// e.g. the cubic metres dug out by a dig plan
+func EnclosedPoints(vertices []Point) int64 {
+	return InteriorPoints(vertices) + BoundaryPoints(vertices)
+}
@@ -0,0 +1,78 @@
+package geom
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestSquare(t *testing.T) {
+	// 3x3 square of lattice points, anticlockwise with Y up
+	square := []Point{{0, 0}, {2, 0}, {2, 2}, {0, 2}}
+
+	if got := DoubleArea(square); got != 8 {
+		t.Errorf("expected a double area of 8, got %d", got)
+	}
+	if got := BoundaryPoints(square); got != 8 {
+		t.Errorf("expected 8 boundary points, got %d", got)
+	}
+	if got := InteriorPoints(square); got != 1 {
+		t.Errorf("expected 1 interior point, got %d", got)
+	}
+	if got := EnclosedPoints(square); got != 9 {
+		t.Errorf("expected 9 enclosed points, got %d", got)
+	}
+}
+
+func TestTriangle(t *testing.T) {
+	// the diagonal edge passes through (2, 2) as well as its end points
+	triangle := []Point{{0, 0}, {4, 0}, {0, 4}}
+	if got := BoundaryPoints(triangle); got != 12 {
+		t.Errorf("expected 12 boundary points, got %d", got)
+	}
+	if got := InteriorPoints(triangle); got != 3 {
+		t.Errorf("expected 3 interior points, got %d", got)
+	}
+}
+
+func TestDigPlan(t *testing.T) {
+	plan := []struct {
+		direction byte
+		length    int64
+	}{
+		{'R', 6}, {'D', 5}, {'L', 2}, {'D', 2}, {'R', 2}, {'D', 2}, {'L', 5},
+		{'U', 2}, {'L', 1}, {'U', 2}, {'R', 2}, {'U', 3}, {'L', 2}, {'U', 2},
+	}
+	moves := make([]Move, len(plan))
+	for i, step := range plan {
+		direction, err := ParseDirection(step.direction)
+		if err != nil {
+			t.Fatal(err)
+		}
+		moves[i] = Move{Direction: direction, Length: step.length}
+	}
+
+	vertices := Trace(Point{}, moves)
+	if last := vertices[len(vertices)-1]; last != (Point{}) {
+		t.Fatalf("expected the plan to return to the start, got %v", last)
+	}
+	if got := EnclosedPoints(vertices); got != 62 {
+		t.Fatalf("expected 62, got %d", got)
+	}
+}
+
+func TestBig(t *testing.T) {
+	const side = 4_000_000_000
+	square := []Point{{0, 0}, {side, 0}, {side, side}, {0, side}}
+
+	want := new(big.Int).Mul(big.NewInt(side), big.NewInt(side))
+	want.Mul(want, big.NewInt(2))
+	if got := BigDoubleArea(square); got.Cmp(want) != 0 {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	interior := big.NewInt(side - 1)
+	interior.Mul(interior, interior)
+	if got := BigInteriorPoints(square); got.Cmp(interior) != 0 {
+		t.Errorf("expected %s, got %s", interior, got)
+	}
+}
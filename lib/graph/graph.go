@@ -0,0 +1,358 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/max-nicholson/advent-of-code-2023/lib/set"
+)
+
+var (
+	ErrCycle      = errors.New("graph has a cycle")
+	ErrUndirected = errors.New("not defined for undirected graphs")
+)
+
+// Edge connects From to To. In an undirected graph it connects them both ways
+type Edge[N comparable, L comparable] struct {
+	From  N
+	To    N
+	Label L
+}
+
+// Graph has nodes of type N and edges labelled with L. Nodes and edges are kept in
+// insertion order, so every traversal and export is deterministic
+type Graph[N comparable, L comparable] struct {
+	directed bool
+	nodes    []N
+	index    map[N]int
+	edges    []Edge[N, L]
+	// adjacency holds, per node index, the indices of the edges leaving it
+	adjacency [][]int
+}
+
+func NewDirected[N comparable, L comparable]() *Graph[N, L] {
+	return &Graph[N, L]{directed: true, index: map[N]int{}}
+}
+
+func NewUndirected[N comparable, L comparable]() *Graph[N, L] {
+	return &Graph[N, L]{directed: false, index: map[N]int{}}
+}
+
+func (g *Graph[N, L]) Directed() bool {
+	return g.directed
+}
+
+// AddNode adds n if it is not already in the graph
+func (g *Graph[N, L]) AddNode(n N) {
+	if _, ok := g.index[n]; ok {
+		return
+	}
+	g.index[n] = len(g.nodes)
+	g.nodes = append(g.nodes, n)
+	g.adjacency = append(g.adjacency, nil)
+}
+
+// AddEdge adds an edge, adding either node if needed
+func (g *Graph[N, L]) AddEdge(from, to N, label L) {
+	g.AddNode(from)
+	g.AddNode(to)
+	i := len(g.edges)
+	g.edges = append(g.edges, Edge[N, L]{From: from, To: to, Label: label})
+	g.adjacency[g.index[from]] = append(g.adjacency[g.index[from]], i)
+	if !g.directed && from != to {
+		g.adjacency[g.index[to]] = append(g.adjacency[g.index[to]], i)
+	}
+}
+
+func (g *Graph[N, L]) HasNode(n N) bool {
+	_, ok := g.index[n]
+	return ok
+}
+
+func (g *Graph[N, L]) Nodes() []N {
+	return append([]N{}, g.nodes...)
+}
+
+func (g *Graph[N, L]) Len() int {
+	return len(g.nodes)
+}
+
+// other returns the end of edge i that is not n
+func (g *Graph[N, L]) other(i int, n N) N {
+	if g.edges[i].From == n {
+		return g.edges[i].To
+	}
+	return g.edges[i].From
+}
+
+// Edges returns the edges leaving n, oriented so that From is n
+func (g *Graph[N, L]) Edges(n N) []Edge[N, L] {
+	i, ok := g.index[n]
+	if !ok {
+		return nil
+	}
+	edges := make([]Edge[N, L], len(g.adjacency[i]))
+	for j, e := range g.adjacency[i] {
+		edges[j] = Edge[N, L]{From: n, To: g.other(e, n), Label: g.edges[e].Label}
+	}
+	return edges
+}
+
+// Successors returns the nodes reachable from n by a single edge
+func (g *Graph[N, L]) Successors(n N) []N {
+	edges := g.Edges(n)
+	successors := make([]N, len(edges))
+	for i, e := range edges {
+		successors[i] = e.To
+	}
+	return successors
+}
+
+// Next follows the first edge leaving from with the given label
+func (g *Graph[N, L]) Next(from N, label L) (N, bool) {
+	if i, ok := g.index[from]; ok {
+		for _, e := range g.adjacency[i] {
+			if g.edges[e].Label == label {
+				return g.other(e, from), true
+			}
+		}
+	}
+	var zero N
+	return zero, false
+}
+
+// BFS visits every node reachable from start in breadth first order, along with its
+// distance in edges from start. Returning false from visit stops the search
+func (g *Graph[N, L]) BFS(start N, visit func(n N, depth int) bool) {
+	if !g.HasNode(start) {
+		return
+	}
+	seen := set.New(start)
+	queue := []N{start}
+	depths := []int{0}
+	for i := 0; i < len(queue); i++ {
+		if !visit(queue[i], depths[i]) {
+			return
+		}
+		for _, next := range g.Successors(queue[i]) {
+			if seen.Contains(next) {
+				continue
+			}
+			seen.Add(next)
+			queue = append(queue, next)
+			depths = append(depths, depths[i]+1)
+		}
+	}
+}
+
+// DFS visits every node reachable from start in depth first preorder.
+// Returning false from visit stops the search
+func (g *Graph[N, L]) DFS(start N, visit func(n N) bool) {
+	if !g.HasNode(start) {
+		return
+	}
+	seen := set.New[N]()
+	var walk func(n N) bool
+	walk = func(n N) bool {
+		seen.Add(n)
+		if !visit(n) {
+			return false
+		}
+		for _, next := range g.Successors(n) {
+			if !seen.Contains(next) && !walk(next) {
+				return false
+			}
+		}
+		return true
+	}
+	walk(start)
+}
+
+// Reachable returns every node reachable from start, including start itself
+func (g *Graph[N, L]) Reachable(start N) set.Set[N] {
+	reachable := set.New[N]()
+	g.BFS(start, func(n N, _ int) bool {
+		reachable.Add(n)
+		return true
+	})
+	return reachable
+}
+
+// TopologicalSort orders the nodes of a directed graph so every edge points forwards,
+// breaking ties by insertion order
+func (g *Graph[N, L]) TopologicalSort() ([]N, error) {
+	if !g.directed {
+		return nil, fmt.Errorf("topological sort: %w", ErrUndirected)
+	}
+
+	inDegree := make([]int, len(g.nodes))
+	for _, e := range g.edges {
+		inDegree[g.index[e.To]]++
+	}
+	queue := []int{}
+	for i, d := range inDegree {
+		if d == 0 {
+			queue = append(queue, i)
+		}
+	}
+
+	order := make([]N, 0, len(g.nodes))
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		order = append(order, g.nodes[i])
+		for _, e := range g.adjacency[i] {
+			j := g.index[g.edges[e].To]
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				queue = append(queue, j)
+			}
+		}
+	}
+
+	if len(order) != len(g.nodes) {
+		return nil, fmt.Errorf("topological sort: %w", ErrCycle)
+	}
+	return order, nil
+}
+
+// StronglyConnectedComponents returns the strongly connected components by Tarjan's
+// algorithm, in reverse topological order of the condensed graph.
+// For an undirected graph these are its connected components
+func (g *Graph[N, L]) StronglyConnectedComponents() [][]N {
+	index := make([]int, len(g.nodes))
+	lowLink := make([]int, len(g.nodes))
+	onStack := make([]bool, len(g.nodes))
+	for i := range index {
+		index[i] = -1
+	}
+	stack := []int{}
+	components := [][]N{}
+	counter := 0
+
+	var strongConnect func(v int)
+	strongConnect = func(v int) {
+		index[v] = counter
+		lowLink[v] = counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, e := range g.adjacency[v] {
+			w := g.index[g.other(e, g.nodes[v])]
+			if index[w] == -1 {
+				strongConnect(w)
+				lowLink[v] = min(lowLink[v], lowLink[w])
+			} else if onStack[w] {
+				lowLink[v] = min(lowLink[v], index[w])
+			}
+		}
+
+		if lowLink[v] == index[v] {
+			component := []N{}
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, g.nodes[w])
+				if w == v {
+					break
+				}
+			}
+			components = append(components, component)
+		}
+	}
+
+	for v := range g.nodes {
+		if index[v] == -1 {
+			strongConnect(v)
+		}
+	}
+	return components
+}
+
+// FindCycle returns the nodes of a simple cycle, in order, if the graph has one.
+// The first node is not repeated at the end
+func (g *Graph[N, L]) FindCycle() ([]N, bool) {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make([]int, len(g.nodes))
+	path := []int{}
+
+	var visit func(v int, via int) []N
+	visit = func(v int, via int) []N {
+		state[v] = inProgress
+		path = append(path, v)
+		for _, e := range g.adjacency[v] {
+			// an undirected edge leads straight back the way we came
+			if !g.directed && e == via {
+				continue
+			}
+			w := g.index[g.other(e, g.nodes[v])]
+			switch state[w] {
+			case inProgress:
+				start := len(path) - 1
+				for path[start] != w {
+					start--
+				}
+				cycle := make([]N, 0, len(path)-start)
+				for _, i := range path[start:] {
+					cycle = append(cycle, g.nodes[i])
+				}
+				return cycle
+			case unvisited:
+				if cycle := visit(w, e); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[v] = done
+		return nil
+	}
+
+	for v := range g.nodes {
+		if state[v] == unvisited {
+			if cycle := visit(v, -1); cycle != nil {
+				return cycle, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func quote(v any) string {
+	return fmt.Sprintf("%q", fmt.Sprint(v))
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Edge labels are omitted when
+// they are the zero value of L
+func (g *Graph[N, L]) WriteDOT(w io.Writer, name string) error {
+	kind, arrow := "graph", "--"
+	if g.directed {
+		kind, arrow = "digraph", "->"
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s {\n", kind, quote(name))
+	for _, n := range g.nodes {
+		fmt.Fprintf(&sb, "\t%s;\n", quote(n))
+	}
+	var zero L
+	for _, e := range g.edges {
+		fmt.Fprintf(&sb, "\t%s %s %s", quote(e.From), arrow, quote(e.To))
+		if e.Label != zero {
+			fmt.Fprintf(&sb, " [label=%s]", quote(e.Label))
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTraversal(t *testing.T) {
+	g := NewDirected[string, string]()
+	g.AddEdge("a", "b", "L")
+	g.AddEdge("a", "c", "R")
+	g.AddEdge("b", "d", "L")
+	g.AddEdge("c", "d", "L")
+	g.AddNode("e")
+
+	var bfs []string
+	g.BFS("a", func(n string, depth int) bool {
+		bfs = append(bfs, fmt.Sprintf("%s%d", n, depth))
+		return true
+	})
+	if got := strings.Join(bfs, " "); got != "a0 b1 c1 d2" {
+		t.Errorf("BFS: expected a0 b1 c1 d2, got %s", got)
+	}
+
+	var dfs []string
+	g.DFS("a", func(n string) bool {
+		dfs = append(dfs, n)
+		return true
+	})
+	if got := strings.Join(dfs, " "); got != "a b d c" {
+		t.Errorf("DFS: expected a b d c, got %s", got)
+	}
+
+	if got := g.Reachable("b"); got.Len() != 2 || !got.Contains("d") {
+		t.Errorf("expected b to reach {b, d}, got %v", got)
+	}
+	if next, ok := g.Next("a", "R"); !ok || next != "c" {
+		t.Errorf("expected a -R-> c, got %s", next)
+	}
+
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(order, []string{"a", "e", "b", "c", "d"}) {
+		t.Errorf("expected [a e b c d], got %v", order)
+	}
+	if _, ok := g.FindCycle(); ok {
+		t.Errorf("expected no cycle")
+	}
+}
+
+func TestCycles(t *testing.T) {
+	g := NewDirected[int, string]()
+	g.AddEdge(1, 2, "")
+	g.AddEdge(2, 3, "")
+	g.AddEdge(3, 1, "")
+	g.AddEdge(3, 4, "")
+	g.AddEdge(4, 5, "")
+	g.AddEdge(5, 4, "")
+
+	cycle, ok := g.FindCycle()
+	if !ok || !slices.Equal(cycle, []int{1, 2, 3}) {
+		t.Errorf("expected cycle [1 2 3], got %v", cycle)
+	}
+	if _, err := g.TopologicalSort(); !errors.Is(err, ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+
+	components := g.StronglyConnectedComponents()
+	got := make([]string, len(components))
+	for i, component := range components {
+		slices.Sort(component)
+		got[i] = fmt.Sprint(component)
+	}
+	if strings.Join(got, " ") != "[4 5] [1 2 3]" {
+		t.Errorf("expected [4 5] [1 2 3], got %v", got)
+	}
+}
+
+func TestUndirected(t *testing.T) {
+	g := NewUndirected[string, string]()
+	g.AddEdge("a", "b", "")
+	g.AddEdge("b", "c", "")
+
+	if _, ok := g.FindCycle(); ok {
+		t.Errorf("expected a path to have no cycle")
+	}
+	if got := g.Successors("b"); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("expected b to neighbour [a c], got %v", got)
+	}
+
+	g.AddEdge("c", "a", "")
+	if cycle, ok := g.FindCycle(); !ok || len(cycle) != 3 {
+		t.Errorf("expected a triangle, got %v", cycle)
+	}
+	if _, err := g.TopologicalSort(); !errors.Is(err, ErrUndirected) {
+		t.Errorf("expected ErrUndirected, got %v", err)
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	g := NewDirected[string, string]()
+	g.AddEdge("AAA", "BBB", "L")
+	g.AddEdge("AAA", "CCC", "")
+
+	var sb strings.Builder
+	if err := g.WriteDOT(&sb, "network"); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "network" {
+	"AAA";
+	"BBB";
+	"CCC";
+	"AAA" -> "BBB" [label="L"];
+	"AAA" -> "CCC";
+}
+`
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+}
@@ -0,0 +1,136 @@
+package lib
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+)
+
+// ImageOptions controls how a grid is drawn as an image, one solid square per cell
+type ImageOptions struct {
+	// CellSize is the width and height of each cell in pixels, 4 if unset
+	CellSize int
+	// Palette is the colour of each rune. Runes not in the palette are drawn in Background
+	Palette map[rune]color.Color
+	// Background is black if unset
+	Background color.Color
+}
+
+func (o ImageOptions) cellSize() int {
+	if o.CellSize <= 0 {
+		return 4
+	}
+	return o.CellSize
+}
+
+// cellColour is the colour of the cell after overlays: the overlay's background if
+// any, then its foreground, then the palette colour of the (possibly replaced) rune
+func (o ImageOptions) cellColour(grid RuneGrid, overlays []Overlay, row, column int) color.Color {
+	style := Style{Rune: grid.Rune(row, column)}
+	for _, overlay := range overlays {
+		if s, ok := overlay.Style(row, column); ok {
+			style = s.over(style)
+		}
+	}
+	if style.Background != nil {
+		return style.Background
+	}
+	if style.Foreground != nil {
+		return style.Foreground
+	}
+	if c, ok := o.Palette[style.Rune]; ok {
+		return c
+	}
+	if o.Background != nil {
+		return o.Background
+	}
+	return color.Black
+}
+
+// Image draws grid with overlays applied, in the same way as Renderer.Render
+func (o ImageOptions) Image(grid RuneGrid, overlays ...Overlay) *image.RGBA {
+	size := o.cellSize()
+	img := image.NewRGBA(image.Rect(0, 0, grid.Columns()*size, grid.Rows()*size))
+	for row := 0; row < grid.Rows(); row++ {
+		for column := 0; column < grid.Columns(); column++ {
+			cell := image.Rect(column*size, row*size, (column+1)*size, (row+1)*size)
+			c := o.cellColour(grid, overlays, row, column)
+			draw.Draw(img, cell, image.NewUniform(c), image.Point{}, draw.Src)
+		}
+	}
+	return img
+}
+
+// WritePNG writes grid with overlays applied to w as a PNG
+func (o ImageOptions) WritePNG(w io.Writer, grid RuneGrid, overlays ...Overlay) error {
+	return png.Encode(w, o.Image(grid, overlays...))
+}
+
+// Animation collects frames of a simulation to write as an animated GIF
+type Animation struct {
+	Options ImageOptions
+	// Delay is the time each frame is shown for, in hundredths of a second
+	Delay int
+	// palette is every colour drawn so far, up to the 256 a GIF allows. Frames index
+	// into it, and it only ever grows, so earlier frames stay valid
+	palette color.Palette
+	indices map[color.RGBA]uint8
+	frames  []*image.Paletted
+}
+
+func NewAnimation(options ImageOptions, delay int) *Animation {
+	return &Animation{Options: options, Delay: delay, indices: map[color.RGBA]uint8{}}
+}
+
+// AddFrame draws the current state of grid as the next frame. Frames are kept
+// paletted, at one byte per pixel, rather than as full colour images
+func (a *Animation) AddFrame(grid RuneGrid, overlays ...Overlay) {
+	size := a.Options.cellSize()
+	frame := image.NewPaletted(image.Rect(0, 0, grid.Columns()*size, grid.Rows()*size), nil)
+	for row := 0; row < grid.Rows(); row++ {
+		for column := 0; column < grid.Columns(); column++ {
+			index := a.index(a.Options.cellColour(grid, overlays, row, column))
+			for y := row * size; y < (row+1)*size; y++ {
+				for x := column * size; x < (column+1)*size; x++ {
+					frame.SetColorIndex(x, y, index)
+				}
+			}
+		}
+	}
+	a.frames = append(a.frames, frame)
+}
+
+func (a *Animation) Len() int {
+	return len(a.frames)
+}
+
+// index returns the palette index of c, adding c to the palette while there is room
+// and using the closest colour already in it once there isn't
+func (a *Animation) index(c color.Color) uint8 {
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	if i, ok := a.indices[rgba]; ok {
+		return i
+	}
+	if len(a.palette) == 256 {
+		return uint8(a.palette.Index(rgba))
+	}
+	i := uint8(len(a.palette))
+	a.indices[rgba] = i
+	a.palette = append(a.palette, rgba)
+	return i
+}
+
+// WriteGIF writes every frame to w as a looping GIF. Frames use their exact colours
+// where there are 256 or fewer across the animation, and the closest of the first 256 otherwise
+func (a *Animation) WriteGIF(w io.Writer) error {
+	animation := &gif.GIF{}
+	for _, frame := range a.frames {
+		frame.Palette = a.palette
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, a.Delay)
+	}
+	return gif.EncodeAll(w, animation)
+}
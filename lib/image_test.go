@@ -0,0 +1,107 @@
+package lib
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+var testImageOptions = ImageOptions{
+	CellSize: 2,
+	Palette:  map[rune]color.Color{'#': color.White},
+}
+
+func TestWritePNG(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	var buf bytes.Buffer
+	err := testImageOptions.WritePNG(&buf, Lines{"#.", ".."}, HighlightCells([]Cell{{1, 1}}, Style{Background: red}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := img.Bounds().Size(); got.X != 4 || got.Y != 4 {
+		t.Fatalf("expected a 4x4 image, got %v", got)
+	}
+	var testCases = []struct {
+		x, y int
+		want color.Color
+	}{
+		{x: 1, y: 1, want: color.White},
+		{x: 2, y: 0, want: color.Black},
+		{x: 3, y: 3, want: red},
+	}
+	for _, testCase := range testCases {
+		if !sameColour(img.At(testCase.x, testCase.y), testCase.want) {
+			t.Errorf("(%d, %d): expected %v, got %v", testCase.x, testCase.y, testCase.want, img.At(testCase.x, testCase.y))
+		}
+	}
+}
+
+func sameColour(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+func TestWriteGIF(t *testing.T) {
+	animation := NewAnimation(testImageOptions, 10)
+	animation.AddFrame(Lines{"#.", ".."})
+	animation.AddFrame(Lines{".#", ".."})
+
+	var buf bytes.Buffer
+	if err := animation.WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Image) != 2 || decoded.Delay[1] != 10 {
+		t.Fatalf("expected 2 frames of 10, got %d frames with delays %v", len(decoded.Image), decoded.Delay)
+	}
+	if !sameColour(decoded.Image[1].At(3, 0), color.White) || !sameColour(decoded.Image[1].At(0, 0), color.Black) {
+		t.Fatalf("expected the second frame to have exact colours")
+	}
+}
+
+// runeRow is a single row grid of any runes, where Lines only holds bytes
+type runeRow []rune
+
+func (r runeRow) Rows() int                 { return 1 }
+func (r runeRow) Columns() int              { return len(r) }
+func (r runeRow) Rune(row, column int) rune { return r[column] }
+
+func TestWriteGIFManyColours(t *testing.T) {
+	options := ImageOptions{CellSize: 1, Palette: map[rune]color.Color{}}
+	row := runeRow{}
+	for i := 0; i < 300; i++ {
+		r := rune('A' + i)
+		options.Palette[r] = color.RGBA{R: uint8(i), G: uint8(i / 256), A: 255}
+		row = append(row, r)
+	}
+	animation := NewAnimation(options, 10)
+	animation.AddFrame(row)
+
+	var buf bytes.Buffer
+	if err := animation.WriteGIF(&buf); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (color.RGBA{R: 255, A: 255}); !sameColour(decoded.Image[0].At(255, 0), want) {
+		t.Fatalf("expected %v, got %v", want, decoded.Image[0].At(255, 0))
+	}
+	// past 256 colours, cells take the closest colour already in the palette, so
+	// {43, 1, 0} is drawn as {43, 0, 0}
+	if want := (color.RGBA{R: 43, A: 255}); !sameColour(decoded.Image[0].At(299, 0), want) {
+		t.Fatalf("expected %v, got %v", want, decoded.Image[0].At(299, 0))
+	}
+}
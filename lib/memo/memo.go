@@ -0,0 +1,115 @@
+package memo
+
+import "container/list"
+
+type Stats struct {
+	Hits      int
+	Misses    int
+	Evictions int
+}
+
+// Memo caches values by exact key. A bounded Memo evicts the least recently used
+// entry once it holds more than its capacity
+type Memo[K comparable, V any] struct {
+	capacity int
+	entries  map[K]*list.Element
+	// recency is only maintained for bounded memos, most recently used at the front
+	recency *list.List
+	stats   Stats
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// New returns an unbounded Memo
+func New[K comparable, V any]() *Memo[K, V] {
+	return NewBounded[K, V](0)
+}
+
+// NewBounded returns a Memo holding at most capacity entries, or unbounded if capacity <= 0
+func NewBounded[K comparable, V any](capacity int) *Memo[K, V] {
+	return &Memo[K, V]{
+		capacity: max(capacity, 0),
+		entries:  map[K]*list.Element{},
+		recency:  list.New(),
+	}
+}
+
+// Get returns the cached value for key, counting a hit or a miss
+func (m *Memo[K, V]) Get(key K) (V, bool) {
+	element, ok := m.entries[key]
+	if !ok {
+		m.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	m.stats.Hits++
+	if m.capacity > 0 {
+		m.recency.MoveToFront(element)
+	}
+	return element.Value.(*entry[K, V]).value, true
+}
+
+func (m *Memo[K, V]) Set(key K, value V) {
+	if element, ok := m.entries[key]; ok {
+		element.Value.(*entry[K, V]).value = value
+		if m.capacity > 0 {
+			m.recency.MoveToFront(element)
+		}
+		return
+	}
+
+	m.entries[key] = m.recency.PushFront(&entry[K, V]{key: key, value: value})
+	if m.capacity > 0 && m.recency.Len() > m.capacity {
+		oldest := m.recency.Back()
+		m.recency.Remove(oldest)
+		delete(m.entries, oldest.Value.(*entry[K, V]).key)
+		m.stats.Evictions++
+	}
+}
+
+// Do returns the cached value for key, calling fn and caching its result on a miss
+func (m *Memo[K, V]) Do(key K, fn func() V) V {
+	if value, ok := m.Get(key); ok {
+		return value
+	}
+	value := fn()
+	m.Set(key, value)
+	return value
+}
+
+func (m *Memo[K, V]) Len() int {
+	return len(m.entries)
+}
+
+func (m *Memo[K, V]) Stats() Stats {
+	return m.stats
+}
+
+// Reset empties the memo and zeroes its statistics
+func (m *Memo[K, V]) Reset() {
+	m.entries = map[K]*list.Element{}
+	m.recency.Init()
+	m.stats = Stats{}
+}
+
+// Recursive memoizes a recursive function through m. fn receives the memoized
+// function itself to make its recursive calls, e.g.
+//
+//	fib := memo.Recursive(memo.New[int, int](), func(fib func(int) int, n int) int {
+//		if n < 2 {
+//			return n
+//		}
+//		return fib(n-1) + fib(n-2)
+//	})
+func Recursive[K comparable, V any](m *Memo[K, V], fn func(self func(K) V, key K) V) func(K) V {
+	var self func(K) V
+	self = func(key K) V {
+		return m.Do(key, func() V {
+			return fn(self, key)
+		})
+	}
+	return self
+}
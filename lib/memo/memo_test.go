@@ -0,0 +1,49 @@
+package memo
+
+import "testing"
+
+func TestRecursive(t *testing.T) {
+	m := New[int, int]()
+	fib := Recursive(m, func(fib func(int) int, n int) int {
+		if n < 2 {
+			return n
+		}
+		return fib(n-1) + fib(n-2)
+	})
+
+	if got := fib(90); got != 2880067194370816120 {
+		t.Fatalf("expected 2880067194370816120, got %d", got)
+	}
+	stats := m.Stats()
+	if stats.Misses != 91 || stats.Hits != 88 {
+		t.Fatalf("expected 91 misses and 88 hits, got %+v", stats)
+	}
+
+	fib(90)
+	if got := m.Stats().Hits; got != 89 {
+		t.Fatalf("expected a repeated call to hit, got %d hits", got)
+	}
+}
+
+func TestBounded(t *testing.T) {
+	m := NewBounded[string, int](2)
+	m.Set("a", 1)
+	m.Set("b", 2)
+	// "a" becomes the most recently used, so "b" is evicted next
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Fatalf("expected a = 1, got %d, %v", v, ok)
+	}
+	m.Set("c", 3)
+
+	if _, ok := m.Get("b"); ok {
+		t.Fatalf("expected b to be evicted")
+	}
+	if m.Len() != 2 || m.Stats().Evictions != 1 {
+		t.Fatalf("expected 2 entries and 1 eviction, got %d and %+v", m.Len(), m.Stats())
+	}
+
+	m.Reset()
+	if m.Len() != 0 || m.Stats() != (Stats{}) {
+		t.Fatalf("expected an empty memo after Reset")
+	}
+}
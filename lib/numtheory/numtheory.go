@@ -0,0 +1,205 @@
+package numtheory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/bits"
+)
+
+var (
+	ErrOverflow   = errors.New("integer overflow")
+	ErrNoInverse  = errors.New("no modular inverse")
+	ErrNoSolution = errors.New("no solution to system of congruences")
+)
+
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
+
+// GCD returns the greatest common divisor of all values, which is always non-negative
+func GCD(values ...int) int {
+	var result int
+	for _, v := range values {
+		a, b := result, abs(v)
+		for b != 0 {
+			a, b = b, a%b
+		}
+		result = a
+	}
+	return result
+}
+
+// LCM returns the least common multiple of all values, dividing before multiplying
+// so intermediate results never exceed the final answer.
+// It panics if the result overflows an int; use CheckedLCM to handle that case
+func LCM(values ...int) int {
+	result, err := CheckedLCM(values...)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// CheckedLCM is LCM, but returns ErrOverflow rather than panicking
+func CheckedLCM(values ...int) (int, error) {
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	result := abs(values[0])
+	for _, v := range values[1:] {
+		v = abs(v)
+		if result == 0 || v == 0 {
+			result = 0
+			continue
+		}
+		a := result / GCD(result, v)
+		hi, lo := bits.Mul64(uint64(a), uint64(v))
+		if hi != 0 || lo > math.MaxInt {
+			return 0, fmt.Errorf("lcm of %v: %w", values, ErrOverflow)
+		}
+		result = int(lo)
+	}
+	return result, nil
+}
+
+// BigGCD returns the greatest common divisor of all values as a new big.Int
+func BigGCD(values ...*big.Int) *big.Int {
+	result := new(big.Int)
+	for _, v := range values {
+		result.GCD(nil, nil, result, new(big.Int).Abs(v))
+	}
+	return result
+}
+
+// BigLCM returns the least common multiple of all values as a new big.Int
+func BigLCM(values ...*big.Int) *big.Int {
+	result := new(big.Int)
+	for i, v := range values {
+		v = new(big.Int).Abs(v)
+		if i == 0 {
+			result.Set(v)
+			continue
+		}
+		if result.Sign() == 0 || v.Sign() == 0 {
+			result.SetInt64(0)
+			continue
+		}
+		g := new(big.Int).GCD(nil, nil, result, v)
+		result.Quo(result, g)
+		result.Mul(result, v)
+	}
+	return result
+}
+
+// ExtendedGCD returns g = gcd(a, b) along with x and y such that a*x + b*y = g
+func ExtendedGCD(a, b int) (g, x, y int) {
+	oldR, r := a, b
+	oldS, s := 1, 0
+	oldT, t := 0, 1
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, oldS-q*s
+		oldT, t = t, oldT-q*t
+	}
+	if oldR < 0 {
+		return -oldR, -oldS, -oldT
+	}
+	return oldR, oldS, oldT
+}
+
+// Mod returns a modulo m in the range [0, m)
+func Mod(a, m int) int {
+	a %= m
+	if a < 0 {
+		a += m
+	}
+	return a
+}
+
+// MulMod returns a*b modulo m without overflowing, for any positive m
+func MulMod(a, b, m int) int {
+	a, b = Mod(a, m), Mod(b, m)
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	return int(bits.Rem64(hi, lo, uint64(m)))
+}
+
+// ModInverse returns x in [0, m) such that a*x = 1 (mod m)
+func ModInverse(a, m int) (int, error) {
+	if m <= 0 {
+		return 0, fmt.Errorf("modulus must be positive, got %d", m)
+	}
+	g, x, _ := ExtendedGCD(Mod(a, m), m)
+	if g != 1 {
+		return 0, fmt.Errorf("%d mod %d: %w", a, m, ErrNoInverse)
+	}
+	return Mod(x, m), nil
+}
+
+// ModPow returns base^exp modulo m, using square-and-multiply
+func ModPow(base, exp, m int) (int, error) {
+	if m <= 0 {
+		return 0, fmt.Errorf("modulus must be positive, got %d", m)
+	}
+	if exp < 0 {
+		inverse, err := ModInverse(base, m)
+		if err != nil {
+			return 0, err
+		}
+		base, exp = inverse, -exp
+	}
+
+	result := 1 % m
+	base = Mod(base, m)
+	for exp > 0 {
+		if exp&1 == 1 {
+			result = MulMod(result, base, m)
+		}
+		base = MulMod(base, base, m)
+		exp >>= 1
+	}
+	return result, nil
+}
+
+// CRT solves the system x = residues[i] (mod moduli[i]), returning the smallest
+// non-negative x and the combined modulus (the LCM of all moduli).
+// Moduli need not be pairwise coprime, but an inconsistent system returns ErrNoSolution
+func CRT(residues, moduli []int) (x int, m int, err error) {
+	if len(residues) != len(moduli) {
+		return 0, 0, fmt.Errorf("mismatch in length between residues (%d) and moduli (%d)", len(residues), len(moduli))
+	}
+
+	x, m = 0, 1
+	for i := range residues {
+		if moduli[i] <= 0 {
+			return 0, 0, fmt.Errorf("modulus %d must be positive, got %d", i, moduli[i])
+		}
+		a, n := Mod(residues[i], moduli[i]), moduli[i]
+
+		g, p, _ := ExtendedGCD(m, n)
+		diff := a - x
+		if diff%g != 0 {
+			return 0, 0, fmt.Errorf("x = %d (mod %d) and x = %d (mod %d): %w", x, m, a, n, ErrNoSolution)
+		}
+
+		lcm, err := CheckedLCM(m, n)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		// x + m*t satisfies both congruences, where t = (diff/g) * p (mod n/g)
+		ng := n / g
+		t := MulMod(diff/g, p, ng)
+		// m*t < lcm and x < m, so neither step can overflow
+		x = x + m*t
+		m = lcm
+	}
+
+	return x, m, nil
+}
@@ -0,0 +1,131 @@
+package numtheory
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestGCD(t *testing.T) {
+	var testCases = []struct {
+		values []int
+		want   int
+	}{
+		{values: []int{12, 18}, want: 6},
+		{values: []int{-12, 18}, want: 6},
+		{values: []int{0, 5}, want: 5},
+		{values: []int{12, 18, 8}, want: 2},
+		{values: []int{}, want: 0},
+	}
+	for _, testCase := range testCases {
+		if got := GCD(testCase.values...); got != testCase.want {
+			t.Errorf("GCD(%v): expected %d, got %d", testCase.values, testCase.want, got)
+		}
+	}
+}
+
+func TestLCM(t *testing.T) {
+	var testCases = []struct {
+		values []int
+		want   int
+	}{
+		{values: []int{4, 6}, want: 12},
+		{values: []int{2, 3, 4}, want: 12},
+		{values: []int{7}, want: 7},
+		{values: []int{0, 7}, want: 0},
+		// a * b would overflow, but the LCM itself does not
+		{values: []int{math.MaxInt / 2, math.MaxInt / 2}, want: math.MaxInt / 2},
+	}
+	for _, testCase := range testCases {
+		if got := LCM(testCase.values...); got != testCase.want {
+			t.Errorf("LCM(%v): expected %d, got %d", testCase.values, testCase.want, got)
+		}
+	}
+}
+
+func TestCheckedLCMOverflow(t *testing.T) {
+	_, err := CheckedLCM(math.MaxInt/2, math.MaxInt/2-1)
+	if !errors.Is(err, ErrOverflow) {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+}
+
+func TestBigLCM(t *testing.T) {
+	a := new(big.Int).Lsh(big.NewInt(1), 100)
+	b := big.NewInt(3)
+	want := new(big.Int).Mul(a, b)
+	if got := BigLCM(a, b); got.Cmp(want) != 0 {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got := BigGCD(big.NewInt(-12), big.NewInt(18)); got.Int64() != 6 {
+		t.Fatalf("expected 6, got %s", got)
+	}
+}
+
+func TestExtendedGCD(t *testing.T) {
+	g, x, y := ExtendedGCD(240, 46)
+	if g != 2 || 240*x+46*y != g {
+		t.Fatalf("expected 240*%d + 46*%d = 2, got %d", x, y, g)
+	}
+}
+
+func TestModInverse(t *testing.T) {
+	got, err := ModInverse(3, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 4 {
+		t.Fatalf("expected 4, got %d", got)
+	}
+	if _, err := ModInverse(4, 8); !errors.Is(err, ErrNoInverse) {
+		t.Fatalf("expected ErrNoInverse, got %v", err)
+	}
+}
+
+func TestModPow(t *testing.T) {
+	var testCases = []struct {
+		base, exp, mod int
+		want           int
+	}{
+		{base: 4, exp: 13, mod: 497, want: 445},
+		{base: 2, exp: 0, mod: 1, want: 0},
+		{base: 3, exp: -1, mod: 11, want: 4},
+		{base: math.MaxInt - 1, exp: 2, mod: math.MaxInt, want: 1},
+	}
+	for _, testCase := range testCases {
+		got, err := ModPow(testCase.base, testCase.exp, testCase.mod)
+		if err != nil {
+			t.Error(err)
+		}
+		if got != testCase.want {
+			t.Errorf("ModPow(%d, %d, %d): expected %d, got %d", testCase.base, testCase.exp, testCase.mod, testCase.want, got)
+		}
+	}
+}
+
+func TestCRT(t *testing.T) {
+	var testCases = []struct {
+		residues []int
+		moduli   []int
+		x, m     int
+	}{
+		{residues: []int{2, 3, 2}, moduli: []int{3, 5, 7}, x: 23, m: 105},
+		// non-coprime moduli
+		{residues: []int{2, 4}, moduli: []int{6, 8}, x: 20, m: 24},
+		{residues: []int{-1}, moduli: []int{5}, x: 4, m: 5},
+	}
+	for _, testCase := range testCases {
+		x, m, err := CRT(testCase.residues, testCase.moduli)
+		if err != nil {
+			t.Error(err)
+		}
+		if x != testCase.x || m != testCase.m {
+			t.Errorf("expected %d (mod %d), got %d (mod %d)", testCase.x, testCase.m, x, m)
+		}
+	}
+
+	if _, _, err := CRT([]int{1, 2}, []int{4, 6}); !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("expected ErrNoSolution, got %v", err)
+	}
+}
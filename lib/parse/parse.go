@@ -0,0 +1,201 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseError reports where in the input a parse failed.
+// Line and Column are 1-based; Line is 0 when the line number is not known,
+// see Each for attaching it
+type ParseError struct {
+	Line   int
+	Column int
+	Text   string
+	Msg    string
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	if e.Line > 0 {
+		fmt.Fprintf(&sb, "line %d, ", e.Line)
+	}
+	fmt.Fprintf(&sb, "column %d: %s %q", e.Column, e.Msg, e.Text)
+	if e.Err != nil {
+		fmt.Fprintf(&sb, ": %v", e.Err)
+	}
+	return sb.String()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func errorAt(offset int, text string, msg string, err error) *ParseError {
+	return &ParseError{Column: offset + 1, Text: text, Msg: msg, Err: err}
+}
+
+// Each parses every line with fn, stopping at the first error.
+// Any *ParseError returned by fn has its Line set; other errors are wrapped with the line number
+func Each[T any](lines []string, fn func(line string) (T, error)) ([]T, error) {
+	results := make([]T, len(lines))
+	for i, line := range lines {
+		result, err := fn(line)
+		if err != nil {
+			var parseErr *ParseError
+			if errors.As(err, &parseErr) {
+				if parseErr.Line == 0 {
+					parseErr.Line = i + 1
+				}
+				return nil, err
+			}
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// intAt returns the end offset of the signed integer starting at offset, or -1
+func intAt(line string, offset int) int {
+	i := offset
+	if i < len(line) && (line[i] == '-' || line[i] == '+') {
+		i++
+	}
+	start := i
+	for i < len(line) && isDigit(line[i]) {
+		i++
+	}
+	if i == start {
+		return -1
+	}
+	return i
+}
+
+// Ints extracts every signed integer from line, ignoring any other text.
+// A '-' or '+' directly before a digit is treated as a sign
+func Ints(line string) ([]int, error) {
+	return intsFrom(line, 0, len(line))
+}
+
+func intsFrom(line string, start int, end int) ([]int, error) {
+	values := []int{}
+	for i := start; i < end; {
+		j := intAt(line[:end], i)
+		if j == -1 {
+			i++
+			continue
+		}
+		v, err := strconv.Atoi(line[i:j])
+		if err != nil {
+			return nil, errorAt(i, line[i:j], "invalid integer", err)
+		}
+		values = append(values, v)
+		i = j
+	}
+	return values, nil
+}
+
+// Split slices s into the fields between any of the separators, like strings.Split
+// with more than one separator. When separators overlap, the longest match wins
+func Split(s string, separators ...string) []string {
+	fields := []string{}
+	start := 0
+	for i := 0; i < len(s); {
+		longest := 0
+		for _, sep := range separators {
+			if len(sep) > longest && strings.HasPrefix(s[i:], sep) {
+				longest = len(sep)
+			}
+		}
+		if longest == 0 {
+			i++
+			continue
+		}
+		fields = append(fields, s[start:i])
+		i += longest
+		start = i
+	}
+	return append(fields, s[start:])
+}
+
+// Match consumes line from the start according to pieces, and returns whatever is left.
+//
+// A string piece must appear literally. A pointer piece captures a value:
+//   - *int: a signed integer
+//   - *string: text up to the next string piece, or the rest of the line
+//   - *[]int: every integer up to the next string piece, or the rest of the line
+//
+// Spaces before each piece are skipped, so Match(line, "Card", &id, ":", &numbers)
+// accepts "Card   1: 41 48"
+func Match(line string, pieces ...any) (string, error) {
+	offset := 0
+	for p, piece := range pieces {
+		literal, isLiteral := piece.(string)
+		if !isLiteral || (literal != "" && !isSpace(literal[0])) {
+			for offset < len(line) && isSpace(line[offset]) {
+				offset++
+			}
+		}
+
+		// captures that consume text run up to the next literal
+		end := len(line)
+		if p+1 < len(pieces) {
+			if next, ok := pieces[p+1].(string); ok && next != "" {
+				if i := strings.Index(line[offset:], next); i != -1 {
+					end = offset + i
+				}
+			}
+		}
+
+		switch v := piece.(type) {
+		case string:
+			if !strings.HasPrefix(line[offset:], v) {
+				return "", errorAt(offset, excerpt(line[offset:], len(v)), fmt.Sprintf("expected %q, got", v), nil)
+			}
+			offset += len(v)
+		case *int:
+			j := intAt(line, offset)
+			if j == -1 {
+				return "", errorAt(offset, excerpt(line[offset:], 1), "expected integer, got", nil)
+			}
+			n, err := strconv.Atoi(line[offset:j])
+			if err != nil {
+				return "", errorAt(offset, line[offset:j], "invalid integer", err)
+			}
+			*v = n
+			offset = j
+		case *string:
+			*v = strings.TrimRight(line[offset:end], " \t")
+			offset = end
+		case *[]int:
+			values, err := intsFrom(line, offset, end)
+			if err != nil {
+				return "", err
+			}
+			*v = values
+			offset = end
+		default:
+			return "", fmt.Errorf("unsupported capture type %T", piece)
+		}
+	}
+	return line[offset:], nil
+}
+
+func excerpt(s string, n int) string {
+	if n < len(s) {
+		return s[:n]
+	}
+	return s
+}
@@ -0,0 +1,97 @@
+package parse
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestInts(t *testing.T) {
+	var testCases = []struct {
+		line string
+		want []int
+	}{
+		{line: "seeds: 79 14 55 13", want: []int{79, 14, 55, 13}},
+		{line: "Time:      7  15   30", want: []int{7, 15, 30}},
+		{line: "x=-3, y=+4 -", want: []int{-3, 4}},
+		{line: "no numbers here", want: []int{}},
+	}
+	for _, testCase := range testCases {
+		got, err := Ints(testCase.line)
+		if err != nil {
+			t.Error(err)
+		}
+		if !slices.Equal(got, testCase.want) {
+			t.Errorf("%q: expected %v, got %v", testCase.line, testCase.want, got)
+		}
+	}
+
+	_, err := Ints("ok 99999999999999999999")
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Column != 4 {
+		t.Fatalf("expected a ParseError at column 4, got %v", err)
+	}
+	if !errors.Is(err, strconv.ErrRange) {
+		t.Fatalf("expected the error to wrap strconv.ErrRange, got %v", err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	got := Split("Card 1: 41 48 | 83 86", ": ", " | ")
+	want := []string{"Card 1", "41 48", "83 86"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	got = Split("a, b; c", ", ", "; ", ",")
+	want = []string{"a", "b", "c"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	var id int
+	var winning, numbers []int
+	rest, err := Match("Card   3:  1 21 | 69 82", "Card", &id, ":", &winning, "|", &numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 || !slices.Equal(winning, []int{1, 21}) || !slices.Equal(numbers, []int{69, 82}) || rest != "" {
+		t.Fatalf("got id %d, winning %v, numbers %v, rest %q", id, winning, numbers, rest)
+	}
+
+	var sets string
+	rest, err = Match("Game 12: 3 blue; 2 red", "Game", &id, ": ", &sets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 12 || sets != "3 blue; 2 red" {
+		t.Fatalf("got id %d, sets %q", id, sets)
+	}
+
+	_, err = Match("Game x: 3 blue", "Game", &id)
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) || parseErr.Column != 6 || parseErr.Text != "x" {
+		t.Fatalf("expected a ParseError at column 6 for \"x\", got %v", err)
+	}
+}
+
+func TestEach(t *testing.T) {
+	_, err := Each([]string{"Game 1", "Gam 2"}, func(line string) (int, error) {
+		var id int
+		_, err := Match(line, "Game", &id)
+		return id, err
+	})
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("expected a ParseError, got %v", err)
+	}
+	if parseErr.Line != 2 || parseErr.Column != 1 {
+		t.Fatalf("expected line 2, column 1, got %v", parseErr)
+	}
+	if want := `line 2, column 1: expected "Game", got "Gam "`; err.Error() != want {
+		t.Fatalf("expected %q, got %q", want, err.Error())
+	}
+}
@@ -0,0 +1,135 @@
+package poly
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Ints converts integers to exact rationals
+func Ints(values []int) []*big.Rat {
+	rats := make([]*big.Rat, len(values))
+	for i, v := range values {
+		rats[i] = big.NewRat(int64(v), 1)
+	}
+	return rats
+}
+
+// Differences builds the finite difference table of values.
+// Row 0 is values itself, and each subsequent row is the difference between
+// adjacent elements of the row above, stopping once a row is all zeros
+// (or has a single element)
+func Differences(values []*big.Rat) [][]*big.Rat {
+	if len(values) == 0 {
+		return [][]*big.Rat{}
+	}
+	table := [][]*big.Rat{values}
+	for {
+		row := table[len(table)-1]
+		if len(row) <= 1 || allZero(row) {
+			return table
+		}
+		next := make([]*big.Rat, len(row)-1)
+		for i := range next {
+			next[i] = new(big.Rat).Sub(row[i+1], row[i])
+		}
+		table = append(table, next)
+	}
+}
+
+func allZero(values []*big.Rat) bool {
+	for _, v := range values {
+		if v.Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Extrapolate evaluates, at x, the lowest degree polynomial passing through
+// (0, values[0]), (1, values[1]), ..., using Newton's forward difference formula
+func Extrapolate(values []*big.Rat, x int) *big.Rat {
+	result := new(big.Rat)
+	// binomial holds C(x, k), built up incrementally
+	binomial := big.NewRat(1, 1)
+	for k, row := range Differences(values) {
+		if k > 0 {
+			binomial.Mul(binomial, big.NewRat(int64(x-k+1), int64(k)))
+		}
+		result.Add(result, new(big.Rat).Mul(row[0], binomial))
+	}
+	return result
+}
+
+// FitSequence returns the lowest degree polynomial p with p(i) = values[i]
+func FitSequence(values []*big.Rat) Poly {
+	xs := make([]*big.Rat, len(values))
+	for i := range xs {
+		xs[i] = big.NewRat(int64(i), 1)
+	}
+	// consecutive integers are always distinct
+	p, _ := Newton(xs, values)
+	return p
+}
+
+func checkPoints(xs, ys []*big.Rat) error {
+	if len(xs) != len(ys) {
+		return fmt.Errorf("mismatch in length between xs (%d) and ys (%d)", len(xs), len(ys))
+	}
+	seen := make(map[string]struct{}, len(xs))
+	for _, x := range xs {
+		key := x.RatString()
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("x = %s: %w", key, ErrDuplicateX)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
+// Lagrange returns the unique polynomial of degree < len(xs) through every (xs[i], ys[i])
+func Lagrange(xs, ys []*big.Rat) (Poly, error) {
+	if err := checkPoints(xs, ys); err != nil {
+		return nil, err
+	}
+
+	result := Poly{}
+	for i := range xs {
+		basis := Poly{big.NewRat(1, 1)}
+		denominator := big.NewRat(1, 1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			basis = basis.Mul(Poly{new(big.Rat).Neg(xs[j]), big.NewRat(1, 1)})
+			denominator.Mul(denominator, new(big.Rat).Sub(xs[i], xs[j]))
+		}
+		result = result.Add(basis.Scale(new(big.Rat).Quo(ys[i], denominator)))
+	}
+	return result, nil
+}
+
+// Newton returns the same polynomial as Lagrange, built from divided differences
+func Newton(xs, ys []*big.Rat) (Poly, error) {
+	if err := checkPoints(xs, ys); err != nil {
+		return nil, err
+	}
+
+	n := len(xs)
+	coefficients := make([]*big.Rat, n)
+	for i := range ys {
+		coefficients[i] = new(big.Rat).Set(ys[i])
+	}
+	for level := 1; level < n; level++ {
+		for i := n - 1; i >= level; i-- {
+			numerator := new(big.Rat).Sub(coefficients[i], coefficients[i-1])
+			coefficients[i] = numerator.Quo(numerator, new(big.Rat).Sub(xs[i], xs[i-level]))
+		}
+	}
+
+	// Horner-style expansion of c0 + (x - x0)(c1 + (x - x1)(c2 + ...))
+	result := Poly{}
+	for i := n - 1; i >= 0; i-- {
+		result = result.Mul(Poly{new(big.Rat).Neg(xs[i]), big.NewRat(1, 1)}).Add(Poly{coefficients[i]})
+	}
+	return result, nil
+}
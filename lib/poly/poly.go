@@ -0,0 +1,193 @@
+package poly
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var ErrDuplicateX = errors.New("duplicate x value")
+
+// Poly is a polynomial with exact rational coefficients, lowest degree first,
+// so Poly{a, b, c} is a + bx + cx^2. The zero polynomial is the empty Poly
+type Poly []*big.Rat
+
+// New builds a Poly from integer coefficients, lowest degree first
+func New(coefficients ...int64) Poly {
+	p := make(Poly, len(coefficients))
+	for i, c := range coefficients {
+		p[i] = big.NewRat(c, 1)
+	}
+	return p.trim()
+}
+
+func (p Poly) trim() Poly {
+	n := len(p)
+	for n > 0 && p[n-1].Sign() == 0 {
+		n--
+	}
+	return p[:n]
+}
+
+func (p Poly) coefficient(i int) *big.Rat {
+	if i < len(p) {
+		return p[i]
+	}
+	return new(big.Rat)
+}
+
+// Degree returns the degree of p, or -1 for the zero polynomial
+func (p Poly) Degree() int {
+	return len(p.trim()) - 1
+}
+
+// Eval evaluates p at x using Horner's method
+func (p Poly) Eval(x *big.Rat) *big.Rat {
+	result := new(big.Rat)
+	for i := len(p) - 1; i >= 0; i-- {
+		result.Mul(result, x)
+		result.Add(result, p[i])
+	}
+	return result
+}
+
+// EvalInt evaluates p at the integer x
+func (p Poly) EvalInt(x int) *big.Rat {
+	return p.Eval(big.NewRat(int64(x), 1))
+}
+
+func (p Poly) Add(q Poly) Poly {
+	result := make(Poly, max(len(p), len(q)))
+	for i := range result {
+		result[i] = new(big.Rat).Add(p.coefficient(i), q.coefficient(i))
+	}
+	return result.trim()
+}
+
+func (p Poly) Sub(q Poly) Poly {
+	result := make(Poly, max(len(p), len(q)))
+	for i := range result {
+		result[i] = new(big.Rat).Sub(p.coefficient(i), q.coefficient(i))
+	}
+	return result.trim()
+}
+
+func (p Poly) Mul(q Poly) Poly {
+	if len(p) == 0 || len(q) == 0 {
+		return Poly{}
+	}
+	result := make(Poly, len(p)+len(q)-1)
+	for i := range result {
+		result[i] = new(big.Rat)
+	}
+	term := new(big.Rat)
+	for i, a := range p {
+		for j, b := range q {
+			result[i+j].Add(result[i+j], term.Mul(a, b))
+		}
+	}
+	return result.trim()
+}
+
+// Scale multiplies every coefficient of p by k
+func (p Poly) Scale(k *big.Rat) Poly {
+	result := make(Poly, len(p))
+	for i, c := range p {
+		result[i] = new(big.Rat).Mul(c, k)
+	}
+	return result.trim()
+}
+
+// DivMod returns the quotient and remainder of p divided by q
+func (p Poly) DivMod(q Poly) (Poly, Poly, error) {
+	q = q.trim()
+	if len(q) == 0 {
+		return nil, nil, fmt.Errorf("division by the zero polynomial")
+	}
+
+	remainder := make(Poly, len(p))
+	for i, c := range p {
+		remainder[i] = new(big.Rat).Set(c)
+	}
+	remainder = remainder.trim()
+	if len(remainder) < len(q) {
+		return Poly{}, remainder, nil
+	}
+
+	quotient := make(Poly, len(remainder)-len(q)+1)
+	for i := range quotient {
+		quotient[i] = new(big.Rat)
+	}
+	lead := q[len(q)-1]
+	term := new(big.Rat)
+	for shift := len(quotient) - 1; shift >= 0; shift-- {
+		c := new(big.Rat).Quo(remainder[shift+len(q)-1], lead)
+		quotient[shift] = c
+		for j, b := range q {
+			remainder[shift+j].Sub(remainder[shift+j], term.Mul(c, b))
+		}
+	}
+	return quotient.trim(), remainder.trim(), nil
+}
+
+// Derivative returns dp/dx
+func (p Poly) Derivative() Poly {
+	if len(p) <= 1 {
+		return Poly{}
+	}
+	result := make(Poly, len(p)-1)
+	for i := range result {
+		result[i] = new(big.Rat).Mul(p[i+1], big.NewRat(int64(i+1), 1))
+	}
+	return result.trim()
+}
+
+func (p Poly) Equal(q Poly) bool {
+	p, q = p.trim(), q.trim()
+	if len(p) != len(q) {
+		return false
+	}
+	for i := range p {
+		if p[i].Cmp(q[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (p Poly) String() string {
+	p = p.trim()
+	if len(p) == 0 {
+		return "0"
+	}
+	var sb strings.Builder
+	for i := len(p) - 1; i >= 0; i-- {
+		c := p[i]
+		if c.Sign() == 0 {
+			continue
+		}
+		if sb.Len() > 0 {
+			if c.Sign() < 0 {
+				sb.WriteString(" - ")
+			} else {
+				sb.WriteString(" + ")
+			}
+			c = new(big.Rat).Abs(c)
+		}
+		one := c.Cmp(big.NewRat(1, 1)) == 0
+		minusOne := c.Cmp(big.NewRat(-1, 1)) == 0
+		if i == 0 || !(one || minusOne) {
+			sb.WriteString(c.RatString())
+		} else if minusOne {
+			sb.WriteByte('-')
+		}
+		if i >= 1 {
+			sb.WriteByte('x')
+		}
+		if i >= 2 {
+			fmt.Fprintf(&sb, "^%d", i)
+		}
+	}
+	return sb.String()
+}
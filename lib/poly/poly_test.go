@@ -0,0 +1,95 @@
+package poly
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestArithmetic(t *testing.T) {
+	p := New(1, 1)  // 1 + x
+	q := New(-1, 1) // -1 + x
+
+	if got, want := p.Mul(q), New(-1, 0, 1); !got.Equal(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got, want := p.Add(q), New(0, 2); !got.Equal(want) {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if got := p.Sub(p); got.Degree() != -1 {
+		t.Errorf("expected zero polynomial, got %s", got)
+	}
+
+	quotient, remainder, err := New(-1, 0, 1).DivMod(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !quotient.Equal(p) || remainder.Degree() != -1 {
+		t.Errorf("expected (%s, 0), got (%s, %s)", p, quotient, remainder)
+	}
+
+	if got, want := New(3, 0, 2, -1).String(), "-x^3 + 2x^2 + 3"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestInterpolation(t *testing.T) {
+	// y = x^2 / 2 + 1
+	xs := Ints([]int{0, 2, 3})
+	ys := []*big.Rat{big.NewRat(1, 1), big.NewRat(3, 1), big.NewRat(11, 2)}
+	want := Poly{big.NewRat(1, 1), big.NewRat(0, 1), big.NewRat(1, 2)}
+
+	lagrange, err := Lagrange(xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lagrange.Equal(want) {
+		t.Errorf("Lagrange: expected %s, got %s", want, lagrange)
+	}
+
+	newton, err := Newton(xs, ys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !newton.Equal(want) {
+		t.Errorf("Newton: expected %s, got %s", want, newton)
+	}
+
+	if _, err := Lagrange(Ints([]int{1, 1}), Ints([]int{1, 2})); !errors.Is(err, ErrDuplicateX) {
+		t.Errorf("expected ErrDuplicateX, got %v", err)
+	}
+}
+
+func TestExtrapolate(t *testing.T) {
+	var testCases = []struct {
+		values []int
+		x      int
+		want   int64
+	}{
+		{values: []int{0, 3, 6, 9, 12, 15}, x: 6, want: 18},
+		{values: []int{1, 3, 6, 10, 15, 21}, x: 6, want: 28},
+		{values: []int{10, 13, 16, 21, 30, 45}, x: -1, want: 5},
+		{values: []int{7}, x: 100, want: 7},
+		{values: []int{}, x: 3, want: 0},
+	}
+	for _, testCase := range testCases {
+		got := Extrapolate(Ints(testCase.values), testCase.x)
+		if got.Cmp(big.NewRat(testCase.want, 1)) != 0 {
+			t.Errorf("%v at %d: expected %d, got %s", testCase.values, testCase.x, testCase.want, got.RatString())
+		}
+		fit := FitSequence(Ints(testCase.values))
+		if fit.EvalInt(testCase.x).Cmp(got) != 0 {
+			t.Errorf("%v: expected fit %s to agree with Extrapolate at %d", testCase.values, fit, testCase.x)
+		}
+	}
+}
+
+func TestDifferences(t *testing.T) {
+	table := Differences(Ints([]int{1, 3, 6, 10}))
+	if len(table) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(table))
+	}
+	if table[2][0].Cmp(big.NewRat(1, 1)) != 0 || table[3][0].Sign() != 0 {
+		t.Fatalf("expected second differences of 1 and third of 0, got %v", table)
+	}
+}
@@ -0,0 +1,84 @@
+package lib
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Interval is an inclusive range of integers, empty when Start > End
+type Interval struct {
+	Start int
+	End   int
+}
+
+func (i Interval) Empty() bool {
+	return i.Start > i.End
+}
+
+func (i Interval) Len() int {
+	if i.Empty() {
+		return 0
+	}
+	return i.End - i.Start + 1
+}
+
+func (i Interval) Contains(x int) bool {
+	return i.Start <= x && x <= i.End
+}
+
+// QuadraticInterval returns the integers x for which a*x^2 + b*x + c < 0,
+// or <= 0 when strict is false. a must be positive so the interval is bounded.
+// Intermediate values use math/big, so large coefficients are still exact
+func QuadraticInterval(a, b, c int, strict bool) (Interval, error) {
+	empty := Interval{Start: 0, End: -1}
+	if a <= 0 {
+		return empty, fmt.Errorf("expected a positive leading coefficient, got %d", a)
+	}
+
+	A, B, C := big.NewInt(int64(a)), big.NewInt(int64(b)), big.NewInt(int64(c))
+
+	satisfies := func(x *big.Int) bool {
+		// (a*x + b)*x + c
+		v := new(big.Int).Mul(A, x)
+		v.Add(v, B)
+		v.Mul(v, x)
+		v.Add(v, C)
+		if strict {
+			return v.Sign() < 0
+		}
+		return v.Sign() <= 0
+	}
+
+	discriminant := new(big.Int).Mul(B, B)
+	discriminant.Sub(discriminant, new(big.Int).Mul(big.NewInt(4), new(big.Int).Mul(A, C)))
+	if discriminant.Sign() < 0 {
+		return empty, nil
+	}
+	s := new(big.Int).Sqrt(discriminant)
+	twoA := new(big.Int).Mul(big.NewInt(2), A)
+
+	// With s = floor(sqrt(D)), lo is at most the smallest solution and hi at least
+	// the largest, both within 1 of the real roots, so each only ever needs moving
+	// inwards until it satisfies the inequality
+	lo := new(big.Int).Sub(new(big.Int).Neg(B), s)
+	lo.Div(lo, twoA) // Euclidean division floors for a positive divisor
+	hi := new(big.Int).Add(new(big.Int).Neg(B), s)
+	hi.Add(hi, new(big.Int).Sub(twoA, big.NewInt(1)))
+	hi.Div(hi, twoA) // ceiling
+
+	one := big.NewInt(1)
+	for lo.Cmp(hi) <= 0 && !satisfies(lo) {
+		lo.Add(lo, one)
+	}
+	for hi.Cmp(lo) >= 0 && !satisfies(hi) {
+		hi.Sub(hi, one)
+	}
+	if lo.Cmp(hi) > 0 {
+		return empty, nil
+	}
+	if !lo.IsInt64() || !hi.IsInt64() {
+		return empty, fmt.Errorf("interval [%s, %s] does not fit in an int", lo, hi)
+	}
+
+	return Interval{Start: int(lo.Int64()), End: int(hi.Int64())}, nil
+}
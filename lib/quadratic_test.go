@@ -0,0 +1,37 @@
+package lib
+
+import "testing"
+
+func TestQuadraticInterval(t *testing.T) {
+	var testCases = []struct {
+		a, b, c int
+		strict  bool
+		want    Interval
+	}{
+		// x^2 - 7x + 9 < 0
+		{a: 1, b: -7, c: 9, strict: true, want: Interval{Start: 2, End: 5}},
+		// x^2 - 30x + 200 has integer roots 10 and 20, excluded when strict
+		{a: 1, b: -30, c: 200, strict: true, want: Interval{Start: 11, End: 19}},
+		{a: 1, b: -30, c: 200, strict: false, want: Interval{Start: 10, End: 20}},
+		// x^2 - 2x + 1 = (x - 1)^2 only touches zero
+		{a: 1, b: -2, c: 1, strict: false, want: Interval{Start: 1, End: 1}},
+		// 4x^2 - 4x + 1 = (2x - 1)^2 has a non-integer double root
+		{a: 4, b: -4, c: 1, strict: false, want: Interval{Start: 0, End: -1}},
+		// large coefficients where b^2 overflows an int
+		// (x - 3e9)^2 - 1 < 0
+		{a: 1, b: -6_000_000_000, c: 8_999_999_999_999_999_999, strict: true, want: Interval{Start: 3_000_000_000, End: 3_000_000_000}},
+	}
+	for _, testCase := range testCases {
+		got, err := QuadraticInterval(testCase.a, testCase.b, testCase.c, testCase.strict)
+		if err != nil {
+			t.Error(err)
+		}
+		if got.Len() != testCase.want.Len() || (!got.Empty() && got != testCase.want) {
+			t.Errorf("%dx^2 + %dx + %d: expected %v, got %v", testCase.a, testCase.b, testCase.c, testCase.want, got)
+		}
+	}
+
+	if _, err := QuadraticInterval(0, 1, 1, true); err == nil {
+		t.Errorf("expected an error for a non-positive leading coefficient")
+	}
+}
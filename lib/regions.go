@@ -0,0 +1,143 @@
+package lib
+
+// Cell is a position in a grid, or an offset between two positions
+type Cell struct {
+	Row    int
+	Column int
+}
+
+func (c Cell) Add(other Cell) Cell {
+	return Cell{Row: c.Row + other.Row, Column: c.Column + other.Column}
+}
+
+var (
+	// Neighbours4 are the orthogonally adjacent offsets
+	Neighbours4 = []Cell{{-1, 0}, {0, 1}, {1, 0}, {0, -1}}
+	// Neighbours8 are the orthogonally and diagonally adjacent offsets
+	Neighbours8 = []Cell{{-1, -1}, {-1, 0}, {-1, 1}, {0, -1}, {0, 1}, {1, -1}, {1, 0}, {1, 1}}
+)
+
+// FillRules decides which cells of a grid belong together
+type FillRules struct {
+	// Neighbours are the offsets to step to from a cell, Neighbours4 if nil
+	Neighbours []Cell
+	// Passable reports whether a cell can be part of a region at all, every cell if nil
+	Passable func(c Cell) bool
+	// Joined reports whether two passable neighbouring cells belong to the same region,
+	// always if nil. Use it for rules that depend on both cells, e.g. matching characters
+	Joined func(from, to Cell) bool
+}
+
+func (rules *FillRules) neighbours() []Cell {
+	if rules.Neighbours == nil {
+		return Neighbours4
+	}
+	return rules.Neighbours
+}
+
+func (rules *FillRules) passable(c Cell) bool {
+	return rules.Passable == nil || rules.Passable(c)
+}
+
+func (rules *FillRules) joined(from, to Cell) bool {
+	return rules.Joined == nil || rules.Joined(from, to)
+}
+
+// FloodFill returns every cell reachable from start, in breadth first order.
+// It returns nothing if start itself is out of bounds or not passable
+func FloodFill(rows, columns int, start Cell, rules FillRules) []Cell {
+	return flood(rows, columns, start, rules, make([]bool, rows*columns))
+}
+
+// flood fills from start, skipping and marking cells in seen (indexed row-major)
+// so that labeling can share one seen slice across every region
+func flood(rows, columns int, start Cell, rules FillRules, seen []bool) []Cell {
+	inBounds := func(c Cell) bool {
+		return c.Row >= 0 && c.Column >= 0 && c.Row < rows && c.Column < columns
+	}
+	if !inBounds(start) || seen[start.Row*columns+start.Column] || !rules.passable(start) {
+		return []Cell{}
+	}
+
+	seen[start.Row*columns+start.Column] = true
+	filled := []Cell{start}
+	for i := 0; i < len(filled); i++ {
+		cell := filled[i]
+		for _, offset := range rules.neighbours() {
+			next := cell.Add(offset)
+			if !inBounds(next) || seen[next.Row*columns+next.Column] {
+				continue
+			}
+			if !rules.passable(next) || !rules.joined(cell, next) {
+				continue
+			}
+			seen[next.Row*columns+next.Column] = true
+			filled = append(filled, next)
+		}
+	}
+	return filled
+}
+
+type Region struct {
+	ID   int
+	Size int
+	// Min and Max are the top-left and bottom-right corners of the bounding box
+	Min Cell
+	Max Cell
+}
+
+// Labels assigns every passable cell of a grid to a Region
+type Labels struct {
+	Rows    int
+	Columns int
+	// ids holds the region id of each cell in row-major order, or -1 if not passable
+	ids     []int
+	Regions []Region
+}
+
+// At returns the id of the region containing the cell, or -1 if it is out of bounds or not passable
+func (l *Labels) At(c Cell) int {
+	if c.Row < 0 || c.Column < 0 || c.Row >= l.Rows || c.Column >= l.Columns {
+		return -1
+	}
+	return l.ids[c.Row*l.Columns+c.Column]
+}
+
+// Region returns the Region containing the cell
+func (l *Labels) Region(c Cell) (Region, bool) {
+	id := l.At(c)
+	if id == -1 {
+		return Region{}, false
+	}
+	return l.Regions[id], true
+}
+
+// Label splits the passable cells of a grid into connected regions.
+// Region ids count up from 0 in row-major order of each region's first cell
+func Label(rows, columns int, rules FillRules) *Labels {
+	labels := &Labels{Rows: rows, Columns: columns, ids: make([]int, rows*columns), Regions: []Region{}}
+	for i := range labels.ids {
+		labels.ids[i] = -1
+	}
+
+	seen := make([]bool, rows*columns)
+	for row := 0; row < rows; row++ {
+		for column := 0; column < columns; column++ {
+			start := Cell{Row: row, Column: column}
+			cells := flood(rows, columns, start, rules, seen)
+			if len(cells) == 0 {
+				continue
+			}
+
+			region := Region{ID: len(labels.Regions), Min: start, Max: start}
+			for _, cell := range cells {
+				labels.ids[cell.Row*columns+cell.Column] = region.ID
+				region.Size++
+				region.Min = Cell{Row: min(region.Min.Row, cell.Row), Column: min(region.Min.Column, cell.Column)}
+				region.Max = Cell{Row: max(region.Max.Row, cell.Row), Column: max(region.Max.Column, cell.Column)}
+			}
+			labels.Regions = append(labels.Regions, region)
+		}
+	}
+	return labels
+}
@@ -0,0 +1,63 @@
+package lib
+
+import "testing"
+
+func TestFloodFill(t *testing.T) {
+	grid := []string{
+		"..#..",
+		"..#..",
+		"###..",
+		".....",
+	}
+	open := FillRules{Passable: func(c Cell) bool { return grid[c.Row][c.Column] == '.' }}
+
+	if got := len(FloodFill(4, 5, Cell{}, open)); got != 4 {
+		t.Errorf("expected 4 cells in the enclosed corner, got %d", got)
+	}
+	if got := len(FloodFill(4, 5, Cell{Row: 3, Column: 0}, open)); got != 11 {
+		t.Errorf("expected 11 cells outside the corner, got %d", got)
+	}
+	if got := len(FloodFill(4, 5, Cell{Row: 0, Column: 2}, open)); got != 0 {
+		t.Errorf("expected no cells from an impassable start, got %d", got)
+	}
+
+	// the wall is solid, so diagonal steps cannot cut through it either
+	open.Neighbours = Neighbours8
+	if got := len(FloodFill(4, 5, Cell{}, open)); got != 4 {
+		t.Errorf("expected the wall to still hold with 8-connectivity, got %d", got)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	grid := []string{
+		"aab",
+		"abb",
+		"ccb",
+	}
+	labels := Label(3, 3, FillRules{
+		Joined: func(from, to Cell) bool {
+			return grid[from.Row][from.Column] == grid[to.Row][to.Column]
+		},
+	})
+
+	if len(labels.Regions) != 3 {
+		t.Fatalf("expected 3 regions, got %d", len(labels.Regions))
+	}
+	var testCases = []struct {
+		cell   Cell
+		region Region
+	}{
+		{cell: Cell{Row: 1, Column: 0}, region: Region{ID: 0, Size: 3, Min: Cell{0, 0}, Max: Cell{1, 1}}},
+		{cell: Cell{Row: 2, Column: 2}, region: Region{ID: 1, Size: 4, Min: Cell{0, 1}, Max: Cell{2, 2}}},
+		{cell: Cell{Row: 2, Column: 0}, region: Region{ID: 2, Size: 2, Min: Cell{2, 0}, Max: Cell{2, 1}}},
+	}
+	for _, testCase := range testCases {
+		region, ok := labels.Region(testCase.cell)
+		if !ok || region != testCase.region {
+			t.Errorf("%v: expected %+v, got %+v", testCase.cell, testCase.region, region)
+		}
+	}
+	if labels.At(Cell{Row: 3, Column: 0}) != -1 {
+		t.Errorf("expected -1 out of bounds")
+	}
+}
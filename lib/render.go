@@ -0,0 +1,237 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+)
+
+// RuneGrid is anything that can be drawn one rune per cell
+type RuneGrid interface {
+	Rows() int
+	Columns() int
+	Rune(row, column int) rune
+}
+
+// Lines adapts puzzle input lines to a RuneGrid
+type Lines []string
+
+func (l Lines) Rows() int {
+	return len(l)
+}
+
+func (l Lines) Columns() int {
+	if len(l) == 0 {
+		return 0
+	}
+	return len(l[0])
+}
+
+func (l Lines) Rune(row, column int) rune {
+	return rune(l[row][column])
+}
+
+// Style changes how a cell is drawn. Zero fields leave the cell as it was,
+// so overlays can be stacked
+type Style struct {
+	Foreground color.Color
+	Background color.Color
+	// Rune replaces the cell's own rune, unless it is 0
+	Rune rune
+	Bold bool
+}
+
+func (s Style) over(base Style) Style {
+	if s.Foreground != nil {
+		base.Foreground = s.Foreground
+	}
+	if s.Background != nil {
+		base.Background = s.Background
+	}
+	if s.Rune != 0 {
+		base.Rune = s.Rune
+	}
+	base.Bold = base.Bold || s.Bold
+	return base
+}
+
+// Overlay styles some cells of a grid
+type Overlay interface {
+	Style(row, column int) (Style, bool)
+}
+
+// OverlayFunc adapts a function to an Overlay
+type OverlayFunc func(row, column int) (Style, bool)
+
+func (f OverlayFunc) Style(row, column int) (Style, bool) {
+	return f(row, column)
+}
+
+// Highlight applies style to every cell for which highlighted returns true
+func Highlight(highlighted func(row, column int) bool, style Style) Overlay {
+	return OverlayFunc(func(row, column int) (Style, bool) {
+		return style, highlighted(row, column)
+	})
+}
+
+// HighlightCells applies style to each of cells
+func HighlightCells(cells []Cell, style Style) Overlay {
+	lookup := make(map[Cell]struct{}, len(cells))
+	for _, c := range cells {
+		lookup[c] = struct{}{}
+	}
+	return Highlight(func(row, column int) bool {
+		_, ok := lookup[Cell{Row: row, Column: column}]
+		return ok
+	}, style)
+}
+
+var arrows = map[Cell]rune{
+	{Row: -1}:    '↑',
+	{Row: 1}:     '↓',
+	{Column: -1}: '←',
+	{Column: 1}:  '→',
+}
+
+// Path applies style to each cell of path and, unless style sets a Rune,
+// draws an arrow towards the next cell where the step between them is orthogonal
+func Path(path []Cell, style Style) Overlay {
+	styles := make(map[Cell]Style, len(path))
+	for i, c := range path {
+		s := style
+		if s.Rune == 0 && i+1 < len(path) {
+			next := path[i+1]
+			s.Rune = arrows[Cell{Row: next.Row - c.Row, Column: next.Column - c.Column}]
+		}
+		styles[c] = s
+	}
+	return OverlayFunc(func(row, column int) (Style, bool) {
+		s, ok := styles[Cell{Row: row, Column: column}]
+		return s, ok
+	})
+}
+
+// Heat colours each cell's background between low and high in proportion to its value
+func Heat(values [][]int, low, high color.RGBA) Overlay {
+	colour := heatScale(values, low, high)
+	return OverlayFunc(func(row, column int) (Style, bool) {
+		if row >= len(values) || column >= len(values[row]) {
+			return Style{}, false
+		}
+		return Style{Background: colour(values[row][column])}, true
+	})
+}
+
+// heatScale maps values between the smallest and largest of values onto low to high
+func heatScale(values [][]int, low, high color.RGBA) func(v int) color.RGBA {
+	lo, hi := 0, 0
+	first := true
+	for _, row := range values {
+		for _, v := range row {
+			if first || v < lo {
+				lo = v
+			}
+			if first || v > hi {
+				hi = v
+			}
+			first = false
+		}
+	}
+	return func(v int) color.RGBA {
+		t := 0.0
+		if hi > lo {
+			t = float64(v-lo) / float64(hi-lo)
+		}
+		return Lerp(low, high, t)
+	}
+}
+
+// Lerp blends from a to b, with t = 0 giving a and t = 1 giving b
+func Lerp(a, b color.RGBA, t float64) color.RGBA {
+	mix := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t + 0.5)
+	}
+	return color.RGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
+
+// IsTerminal reports whether w is a character device such as a terminal,
+// and NO_COLOR (https://no-color.org) is not set
+func IsTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Renderer draws grids with overlays as text, with ANSI colour when enabled
+type Renderer struct {
+	w      io.Writer
+	Colour bool
+}
+
+// NewRenderer writes to w, with colour enabled only if w is a terminal
+func NewRenderer(w io.Writer) *Renderer {
+	return &Renderer{w: w, Colour: IsTerminal(w)}
+}
+
+func ansiColour(w *bufio.Writer, layer int, c color.Color) {
+	r, g, b, _ := c.RGBA()
+	fmt.Fprintf(w, "\x1b[%d;2;%d;%d;%dm", layer, r>>8, g>>8, b>>8)
+}
+
+func (s Style) plain() bool {
+	return !s.Bold && s.Foreground == nil && s.Background == nil
+}
+
+func (s Style) sameColours(other Style) bool {
+	return s.Bold == other.Bold && s.Foreground == other.Foreground && s.Background == other.Background
+}
+
+// Render draws grid with each overlay applied in turn, so later overlays win
+func (r *Renderer) Render(grid RuneGrid, overlays ...Overlay) error {
+	w := bufio.NewWriter(r.w)
+	for row := 0; row < grid.Rows(); row++ {
+		// the style currently in effect on the terminal
+		var current Style
+		for column := 0; column < grid.Columns(); column++ {
+			style := Style{Rune: grid.Rune(row, column)}
+			for _, overlay := range overlays {
+				if s, ok := overlay.Style(row, column); ok {
+					style = s.over(style)
+				}
+			}
+
+			if r.Colour && !style.sameColours(current) {
+				if !current.plain() {
+					w.WriteString("\x1b[0m")
+				}
+				if style.Bold {
+					w.WriteString("\x1b[1m")
+				}
+				if style.Foreground != nil {
+					ansiColour(w, 38, style.Foreground)
+				}
+				if style.Background != nil {
+					ansiColour(w, 48, style.Background)
+				}
+				current = style
+			}
+			w.WriteRune(style.Rune)
+		}
+		if !current.plain() {
+			w.WriteString("\x1b[0m")
+		}
+		w.WriteByte('\n')
+	}
+	return w.Flush()
+}
@@ -0,0 +1,66 @@
+package lib
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestRenderPlain(t *testing.T) {
+	grid := Lines{
+		"...",
+		".#.",
+		"...",
+	}
+	path := []Cell{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 2}}
+
+	var sb strings.Builder
+	r := NewRenderer(&sb)
+	if r.Colour {
+		t.Fatalf("expected colour to be disabled when not writing to a terminal")
+	}
+	err := r.Render(grid,
+		Path(path, Style{}),
+		HighlightCells([]Cell{{2, 0}}, Style{Rune: '*'}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "→→↓\n.#↓\n*..\n"
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+}
+
+func TestRenderColour(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	var sb strings.Builder
+	r := &Renderer{w: &sb, Colour: true}
+	err := r.Render(Lines{"ab.", "..."}, Highlight(func(row, column int) bool {
+		return row == 0 && column < 2
+	}, Style{Foreground: red}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the colour is set once for the run of highlighted cells, then reset
+	want := "\x1b[38;2;255;0;0mab\x1b[0m.\n...\n"
+	if sb.String() != want {
+		t.Fatalf("expected %q, got %q", want, sb.String())
+	}
+}
+
+func TestHeat(t *testing.T) {
+	black := color.RGBA{A: 255}
+	white := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	heat := Heat([][]int{{1, 3, 5}}, black, white)
+
+	style, ok := heat.Style(0, 1)
+	if !ok || style.Background != (color.RGBA{R: 128, G: 128, B: 128, A: 255}) {
+		t.Fatalf("expected mid grey, got %v", style.Background)
+	}
+	if style, _ := heat.Style(0, 2); style.Background != white {
+		t.Fatalf("expected the hottest cell to be white, got %v", style.Background)
+	}
+}
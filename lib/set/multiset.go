@@ -0,0 +1,121 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Multiset counts occurrences of each value. Values are only present while their count is positive
+type Multiset[T comparable] map[T]int
+
+func NewMultiset[T comparable](values ...T) Multiset[T] {
+	m := Multiset[T]{}
+	for _, v := range values {
+		m.Add(v)
+	}
+	return m
+}
+
+func (m Multiset[T]) Add(v T) {
+	m[v]++
+}
+
+// AddN adds n occurrences of v, removing v entirely if its count drops to zero or below
+func (m Multiset[T]) AddN(v T, n int) {
+	m[v] += n
+	if m[v] <= 0 {
+		delete(m, v)
+	}
+}
+
+// Remove removes one occurrence of v
+func (m Multiset[T]) Remove(v T) {
+	m.AddN(v, -1)
+}
+
+func (m Multiset[T]) Count(v T) int {
+	return m[v]
+}
+
+func (m Multiset[T]) Contains(v T) bool {
+	return m[v] > 0
+}
+
+// Len returns the total number of occurrences
+func (m Multiset[T]) Len() int {
+	total := 0
+	for _, n := range m {
+		total += n
+	}
+	return total
+}
+
+// Distinct returns the Set of values with a positive count
+func (m Multiset[T]) Distinct() Set[T] {
+	s := make(Set[T], len(m))
+	for v := range m {
+		s[v] = struct{}{}
+	}
+	return s
+}
+
+// Union returns a new Multiset with the larger count of each value
+func (m Multiset[T]) Union(other Multiset[T]) Multiset[T] {
+	union := Multiset[T]{}
+	for v, n := range m {
+		union[v] = n
+	}
+	for v, n := range other {
+		union[v] = max(union[v], n)
+	}
+	return union
+}
+
+// Intersection returns a new Multiset with the smaller count of each value
+func (m Multiset[T]) Intersection(other Multiset[T]) Multiset[T] {
+	intersection := Multiset[T]{}
+	for v, n := range m {
+		if o := other[v]; o > 0 {
+			intersection[v] = min(n, o)
+		}
+	}
+	return intersection
+}
+
+// Difference returns a new Multiset with other's counts subtracted from m's
+func (m Multiset[T]) Difference(other Multiset[T]) Multiset[T] {
+	difference := Multiset[T]{}
+	for v, n := range m {
+		if n -= other[v]; n > 0 {
+			difference[v] = n
+		}
+	}
+	return difference
+}
+
+// Sum returns a new Multiset with the counts of m and other added together
+func (m Multiset[T]) Sum(other Multiset[T]) Multiset[T] {
+	sum := Multiset[T]{}
+	for v, n := range m {
+		sum[v] = n
+	}
+	for v, n := range other {
+		sum.AddN(v, n)
+	}
+	return sum
+}
+
+// SortedFunc returns the distinct values of m ordered by compare
+func (m Multiset[T]) SortedFunc(compare func(a, b T) int) []T {
+	values := make([]T, 0, len(m))
+	for v := range m {
+		values = append(values, v)
+	}
+	slices.SortFunc(values, compare)
+	return values
+}
+
+// SortedMultiset returns the distinct values of m in ascending order
+func SortedMultiset[T cmp.Ordered](m Multiset[T]) []T {
+	return m.SortedFunc(cmp.Compare[T])
+}
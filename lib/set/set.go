@@ -0,0 +1,111 @@
+package set
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Set is an unordered collection of distinct values
+type Set[T comparable] map[T]struct{}
+
+func New[T comparable](values ...T) Set[T] {
+	s := make(Set[T], len(values))
+	s.Add(values...)
+	return s
+}
+
+func (s Set[T]) Add(values ...T) {
+	for _, v := range values {
+		s[v] = struct{}{}
+	}
+}
+
+func (s Set[T]) Remove(values ...T) {
+	for _, v := range values {
+		delete(s, v)
+	}
+}
+
+func (s Set[T]) Contains(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+func (s Set[T]) Clone() Set[T] {
+	clone := make(Set[T], len(s))
+	for v := range s {
+		clone[v] = struct{}{}
+	}
+	return clone
+}
+
+func (s Set[T]) Equal(other Set[T]) bool {
+	if len(s) != len(other) {
+		return false
+	}
+	for v := range s {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Union returns a new Set of values in either s or other
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	union := s.Clone()
+	for v := range other {
+		union[v] = struct{}{}
+	}
+	return union
+}
+
+// Intersection returns a new Set of values in both s and other
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	if len(other) < len(s) {
+		s, other = other, s
+	}
+	intersection := Set[T]{}
+	for v := range s {
+		if other.Contains(v) {
+			intersection[v] = struct{}{}
+		}
+	}
+	return intersection
+}
+
+// Difference returns a new Set of values in s but not in other
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	difference := Set[T]{}
+	for v := range s {
+		if !other.Contains(v) {
+			difference[v] = struct{}{}
+		}
+	}
+	return difference
+}
+
+// Values returns the values of s in no particular order
+func (s Set[T]) Values() []T {
+	values := make([]T, 0, len(s))
+	for v := range s {
+		values = append(values, v)
+	}
+	return values
+}
+
+// SortedFunc returns the values of s ordered by compare
+func (s Set[T]) SortedFunc(compare func(a, b T) int) []T {
+	values := s.Values()
+	slices.SortFunc(values, compare)
+	return values
+}
+
+// Sorted returns the values of s in ascending order
+func Sorted[T cmp.Ordered](s Set[T]) []T {
+	return s.SortedFunc(cmp.Compare[T])
+}
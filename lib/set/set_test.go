@@ -0,0 +1,62 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	a := New(1, 2, 3)
+	b := New(3, 4)
+
+	if got := Sorted(a.Union(b)); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("union: expected [1 2 3 4], got %v", got)
+	}
+	if got := Sorted(a.Intersection(b)); !slices.Equal(got, []int{3}) {
+		t.Errorf("intersection: expected [3], got %v", got)
+	}
+	if got := Sorted(a.Difference(b)); !slices.Equal(got, []int{1, 2}) {
+		t.Errorf("difference: expected [1 2], got %v", got)
+	}
+
+	a.Remove(1)
+	a.Add(2, 5)
+	if a.Contains(1) || !a.Contains(5) || a.Len() != 3 {
+		t.Errorf("expected {2 3 5}, got %v", Sorted(a))
+	}
+	if !a.Equal(New(5, 3, 2)) || a.Equal(b) {
+		t.Errorf("expected Equal to compare members")
+	}
+}
+
+func TestMultiset(t *testing.T) {
+	a := NewMultiset("a", "a", "b")
+	b := NewMultiset("a", "c", "c")
+
+	if a.Count("a") != 2 || a.Len() != 3 {
+		t.Errorf("expected 2 a's of 3, got %d of %d", a.Count("a"), a.Len())
+	}
+	if got := a.Union(b); got.Count("a") != 2 || got.Count("c") != 2 || got.Len() != 5 {
+		t.Errorf("union: got %v", got)
+	}
+	if got := a.Intersection(b); got.Count("a") != 1 || got.Len() != 1 {
+		t.Errorf("intersection: got %v", got)
+	}
+	if got := a.Difference(b); got.Count("a") != 1 || got.Count("b") != 1 || got.Len() != 2 {
+		t.Errorf("difference: got %v", got)
+	}
+	if got := a.Sum(b); got.Count("a") != 3 || got.Len() != 6 {
+		t.Errorf("sum: got %v", got)
+	}
+
+	a.Remove("b")
+	if a.Contains("b") {
+		t.Errorf("expected b to be removed")
+	}
+	if got := SortedMultiset(b); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("expected [a c], got %v", got)
+	}
+	if got := Sorted(b.Distinct()); !slices.Equal(got, []string{"a", "c"}) {
+		t.Errorf("expected [a c], got %v", got)
+	}
+}
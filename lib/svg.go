@@ -0,0 +1,131 @@
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+)
+
+// SVGOptions controls how WriteSVG draws a heatmap of a grid with a path over it
+type SVGOptions struct {
+	// CellSize is the width and height of each cell, 10 if unset
+	CellSize int
+	// Low and High are the heatmap colours of the smallest and largest values,
+	// black to white if both are unset
+	Low, High color.RGBA
+	// Stroke is the colour of the path, red if unset
+	Stroke color.RGBA
+	// ArrowEvery draws a direction arrow on every nth step of the path, every step if unset
+	ArrowEvery int
+	// Labels writes each cell's value in its square
+	Labels bool
+}
+
+func (o SVGOptions) withDefaults() SVGOptions {
+	if o.CellSize <= 0 {
+		o.CellSize = 10
+	}
+	if o.Low == (color.RGBA{}) && o.High == (color.RGBA{}) {
+		o.Low = color.RGBA{A: 255}
+		o.High = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+	}
+	if o.Stroke == (color.RGBA{}) {
+		o.Stroke = color.RGBA{R: 255, A: 255}
+	}
+	if o.ArrowEvery <= 0 {
+		o.ArrowEvery = 1
+	}
+	return o
+}
+
+// svgColour formats c as a hex colour, ignoring alpha
+func svgColour(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// svgNumber formats f to at most 2 decimal places, so output doesn't depend on
+// floating point noise
+func svgNumber(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
+
+// WriteSVG draws values as a heatmap, one square per cell, then path as a polyline
+// through the cell centres with an arrow on each step showing its direction.
+// The output depends only on the arguments, so it can be compared byte for byte
+func WriteSVG(w io.Writer, values [][]int, path []Cell, options SVGOptions) error {
+	o := options.withDefaults()
+	size := o.CellSize
+	rows, columns := len(values), 0
+	for _, row := range values {
+		columns = max(columns, len(row))
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n",
+		columns*size, rows*size, columns*size, rows*size)
+
+	colour := heatScale(values, o.Low, o.High)
+	bw.WriteString("<g shape-rendering=\"crispEdges\">\n")
+	for r, row := range values {
+		for c, v := range row {
+			fmt.Fprintf(bw, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"/>\n",
+				c*size, r*size, size, size, svgColour(colour(v)))
+		}
+	}
+	bw.WriteString("</g>\n")
+
+	if o.Labels {
+		fmt.Fprintf(bw, "<g font-family=\"monospace\" font-size=\"%s\" text-anchor=\"middle\" dominant-baseline=\"central\" fill=\"%s\">\n",
+			svgNumber(float64(size)*0.6), svgColour(o.Stroke))
+		for r, row := range values {
+			for c, v := range row {
+				fmt.Fprintf(bw, "<text x=\"%s\" y=\"%s\">%d</text>\n",
+					svgNumber((float64(c)+0.5)*float64(size)), svgNumber((float64(r)+0.5)*float64(size)), v)
+			}
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	centre := func(cell Cell) (float64, float64) {
+		return (float64(cell.Column) + 0.5) * float64(size), (float64(cell.Row) + 0.5) * float64(size)
+	}
+
+	if len(path) > 0 {
+		fmt.Fprintf(bw, "<polyline fill=\"none\" stroke=\"%s\" stroke-width=\"%s\" stroke-linejoin=\"round\" points=\"",
+			svgColour(o.Stroke), svgNumber(float64(size)/5))
+		for i, cell := range path {
+			if i > 0 {
+				bw.WriteByte(' ')
+			}
+			x, y := centre(cell)
+			fmt.Fprintf(bw, "%s,%s", svgNumber(x), svgNumber(y))
+		}
+		bw.WriteString("\"/>\n")
+
+		fmt.Fprintf(bw, "<g fill=\"%s\">\n", svgColour(o.Stroke))
+		for i := o.ArrowEvery - 1; i+1 < len(path); i += o.ArrowEvery {
+			x1, y1 := centre(path[i])
+			x2, y2 := centre(path[i+1])
+			dx, dy := x2-x1, y2-y1
+			length := math.Hypot(dx, dy)
+			if length == 0 {
+				continue
+			}
+			// a triangle pointing along the step, centred on its midpoint
+			ux, uy := dx/length, dy/length
+			mx, my := (x1+x2)/2, (y1+y2)/2
+			half := float64(size) / 4
+			fmt.Fprintf(bw, "<path d=\"M%s %sL%s %sL%s %sZ\"/>\n",
+				svgNumber(mx+ux*half), svgNumber(my+uy*half),
+				svgNumber(mx-ux*half-uy*half), svgNumber(my-uy*half+ux*half),
+				svgNumber(mx-ux*half+uy*half), svgNumber(my-uy*half-ux*half))
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
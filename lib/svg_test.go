@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestWriteSVG(t *testing.T) {
+	values := [][]int{
+		{1, 2},
+		{3, 4},
+	}
+	path := []Cell{{0, 0}, {0, 1}, {1, 1}}
+
+	var sb strings.Builder
+	err := WriteSVG(&sb, values, path, SVGOptions{
+		CellSize: 10,
+		Low:      color.RGBA{A: 255},
+		High:     color.RGBA{R: 255, G: 255, B: 255, A: 255},
+		Stroke:   color.RGBA{B: 255, A: 255},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20">
+<g shape-rendering="crispEdges">
+<rect x="0" y="0" width="10" height="10" fill="#000000"/>
+<rect x="10" y="0" width="10" height="10" fill="#555555"/>
+<rect x="0" y="10" width="10" height="10" fill="#aaaaaa"/>
+<rect x="10" y="10" width="10" height="10" fill="#ffffff"/>
+</g>
+<polyline fill="none" stroke="#0000ff" stroke-width="2" stroke-linejoin="round" points="5,5 15,5 15,15"/>
+<g fill="#0000ff">
+<path d="M12.5 5L7.5 7.5L7.5 2.5Z"/>
+<path d="M15 12.5L12.5 7.5L17.5 7.5Z"/>
+</g>
+</svg>
+`
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+}
+
+func TestWriteSVGArrowEvery(t *testing.T) {
+	path := []Cell{{0, 0}, {0, 1}, {0, 2}, {0, 3}, {0, 4}}
+
+	var sb strings.Builder
+	if err := WriteSVG(&sb, [][]int{{0, 0, 0, 0, 0}}, path, SVGOptions{ArrowEvery: 2, Labels: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if arrows := strings.Count(sb.String(), "<path "); arrows != 2 {
+		t.Fatalf("expected 2 arrows, got %d", arrows)
+	}
+	if labels := strings.Count(sb.String(), "<text "); labels != 5 {
+		t.Fatalf("expected 5 labels, got %d", labels)
+	}
+}
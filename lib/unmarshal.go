@@ -0,0 +1,235 @@
+package lib
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/max-nicholson/advent-of-code-2023/lib/parse"
+)
+
+// Unmarshal fills the struct pointed to by v from line, according to the `aoc`
+// tag on its blank field, e.g.
+//
+//	type Card struct {
+//		_       struct{} `aoc:"Card {ID}: {Winning} | {Numbers}"`
+//		ID      int
+//		Winning []int
+//		Numbers []int
+//	}
+//
+// Each {Field} captures the text up to the next literal in the pattern (or the
+// end of the line), with surrounding spaces trimmed. Captures can be ints, uints,
+// strings, types implementing encoding.TextUnmarshaler, structs with their own
+// pattern, or slices of any of these.
+//
+// Slices are split on whitespace, unless the field has a `sep` tag; an empty
+// `sep:""` splits into single characters. Slices of slices take the separator
+// for each further level from `sep2`, `sep3` and so on.
+//
+// Failures are returned as a *parse.ParseError
+func Unmarshal(line string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("expected a non-nil pointer, got %T", v)
+	}
+	return unmarshalValue(line, 0, rv.Elem(), reflect.StructField{}, 1)
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func unmarshalValue(text string, offset int, v reflect.Value, field reflect.StructField, depth int) error {
+	if v.CanAddr() && v.Addr().Type().Implements(textUnmarshalerType) {
+		if err := v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text)); err != nil {
+			return fieldError(offset, text, field, err)
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return unmarshalValue(text, offset, v.Elem(), field, depth)
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return fieldError(offset, text, field, err)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return fieldError(offset, text, field, err)
+		}
+		v.SetUint(n)
+	case reflect.Slice:
+		return unmarshalSlice(text, offset, v, field, depth)
+	case reflect.Struct:
+		return unmarshalStruct(text, offset, v)
+	default:
+		return fieldError(offset, text, field, fmt.Errorf("unsupported type %s", v.Type()))
+	}
+	return nil
+}
+
+func fieldError(offset int, text string, field reflect.StructField, err error) error {
+	msg := "invalid value"
+	if field.Name != "" {
+		msg = fmt.Sprintf("invalid %s", field.Name)
+	}
+	return &parse.ParseError{Column: offset + 1, Text: text, Msg: msg, Err: err}
+}
+
+type token struct {
+	// offset within text of the element
+	offset int
+	text   string
+}
+
+func splitElements(text string, field reflect.StructField, depth int) []token {
+	key := "sep"
+	if depth > 1 {
+		key = fmt.Sprintf("sep%d", depth)
+	}
+	sep, ok := field.Tag.Lookup(key)
+
+	tokens := []token{}
+	switch {
+	case !ok:
+		start := -1
+		for i := 0; i <= len(text); i++ {
+			if i < len(text) && text[i] != ' ' && text[i] != '\t' {
+				if start == -1 {
+					start = i
+				}
+				continue
+			}
+			if start != -1 {
+				tokens = append(tokens, token{offset: start, text: text[start:i]})
+				start = -1
+			}
+		}
+	case sep == "":
+		for i, r := range text {
+			tokens = append(tokens, token{offset: i, text: string(r)})
+		}
+	default:
+		if text == "" {
+			return tokens
+		}
+		offset := 0
+		for _, s := range strings.Split(text, sep) {
+			trimmed := strings.TrimLeft(s, " \t")
+			tokens = append(tokens, token{
+				offset: offset + len(s) - len(trimmed),
+				text:   strings.TrimRight(trimmed, " \t"),
+			})
+			offset += len(s) + len(sep)
+		}
+	}
+	return tokens
+}
+
+func unmarshalSlice(text string, offset int, v reflect.Value, field reflect.StructField, depth int) error {
+	tokens := splitElements(text, field, depth)
+	slice := reflect.MakeSlice(v.Type(), len(tokens), len(tokens))
+	for i, t := range tokens {
+		if err := unmarshalValue(t.text, offset+t.offset, slice.Index(i), field, depth+1); err != nil {
+			return err
+		}
+	}
+	v.Set(slice)
+	return nil
+}
+
+func pattern(t reflect.Type) (string, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Name != "_" {
+			continue
+		}
+		if p, ok := field.Tag.Lookup("aoc"); ok {
+			return p, true
+		}
+	}
+	return "", false
+}
+
+func unmarshalStruct(text string, offset int, v reflect.Value) error {
+	t := v.Type()
+	p, ok := pattern(t)
+	if !ok {
+		return fmt.Errorf("%s has no `aoc` pattern tag", t)
+	}
+
+	// Split the pattern into alternating literals and {Field} placeholders
+	type piece struct {
+		literal string
+		field   string
+	}
+	pieces := []piece{}
+	for p != "" {
+		open := strings.IndexByte(p, '{')
+		if open == -1 {
+			pieces = append(pieces, piece{literal: p})
+			break
+		}
+		closing := strings.IndexByte(p[open:], '}')
+		if closing == -1 {
+			return fmt.Errorf("%s pattern has an unclosed {", t)
+		}
+		if open > 0 {
+			pieces = append(pieces, piece{literal: p[:open]})
+		}
+		pieces = append(pieces, piece{field: p[open+1 : open+closing]})
+		p = p[open+closing+1:]
+	}
+
+	position := 0
+	for i, pc := range pieces {
+		if pc.field == "" {
+			if !strings.HasPrefix(text[position:], pc.literal) {
+				got := text[position:]
+				if len(got) > len(pc.literal) {
+					got = got[:len(pc.literal)]
+				}
+				return &parse.ParseError{Column: offset + position + 1, Text: got, Msg: fmt.Sprintf("expected %q, got", pc.literal)}
+			}
+			position += len(pc.literal)
+			continue
+		}
+
+		field, ok := t.FieldByName(pc.field)
+		if !ok {
+			return fmt.Errorf("%s has no field %s", t, pc.field)
+		}
+
+		end := len(text)
+		if i+1 < len(pieces) {
+			next := strings.Index(text[position:], pieces[i+1].literal)
+			if next == -1 {
+				return &parse.ParseError{Column: offset + position + 1, Text: text[position:], Msg: fmt.Sprintf("expected %q after %s in", pieces[i+1].literal, pc.field)}
+			}
+			end = position + next
+		}
+
+		capture := text[position:end]
+		trimmed := strings.TrimLeft(capture, " \t")
+		start := position + len(capture) - len(trimmed)
+		if err := unmarshalValue(strings.TrimRight(trimmed, " \t"), offset+start, v.FieldByIndex(field.Index), field, 1); err != nil {
+			return err
+		}
+		position = end
+	}
+
+	if position != len(text) {
+		return &parse.ParseError{Column: offset + position + 1, Text: text[position:], Msg: "unexpected trailing text"}
+	}
+	return nil
+}
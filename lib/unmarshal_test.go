@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/max-nicholson/advent-of-code-2023/lib/parse"
+)
+
+type card struct {
+	_       struct{} `aoc:"Card {ID}: {Winning} | {Numbers}"`
+	ID      int
+	Winning []int
+	Numbers []int
+}
+
+type colour string
+
+func (c *colour) UnmarshalText(text []byte) error {
+	switch s := string(text); s {
+	case "red", "green", "blue":
+		*c = colour(s)
+		return nil
+	default:
+		return fmt.Errorf("unknown colour %s", s)
+	}
+}
+
+type cube struct {
+	_      struct{} `aoc:"{Count} {Colour}"`
+	Count  int
+	Colour colour
+}
+
+type game struct {
+	_    struct{} `aoc:"Game {ID}: {Sets}"`
+	ID   uint
+	Sets [][]cube `sep:"; " sep2:", "`
+}
+
+func TestUnmarshal(t *testing.T) {
+	var c card
+	if err := Unmarshal("Card   3:  1 21 53 | 69 82  1", &c); err != nil {
+		t.Fatal(err)
+	}
+	if c.ID != 3 || !slices.Equal(c.Winning, []int{1, 21, 53}) || !slices.Equal(c.Numbers, []int{69, 82, 1}) {
+		t.Fatalf("got %+v", c)
+	}
+
+	var g game
+	if err := Unmarshal("Game 2: 1 blue, 2 green; 3 green", &g); err != nil {
+		t.Fatal(err)
+	}
+	if g.ID != 2 || len(g.Sets) != 2 || len(g.Sets[0]) != 2 || g.Sets[0][1] != (cube{Count: 2, Colour: "green"}) || g.Sets[1][0] != (cube{Count: 3, Colour: "green"}) {
+		t.Fatalf("got %+v", g)
+	}
+
+	var chars struct {
+		_     struct{} `aoc:"{Cards} {Bid}"`
+		Cards []string `sep:""`
+		Bid   int
+	}
+	if err := Unmarshal("32T3K 765", &chars); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(chars.Cards, ",") != "3,2,T,3,K" || chars.Bid != 765 {
+		t.Fatalf("got %+v", chars)
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var testCases = []struct {
+		line   string
+		v      any
+		column int
+	}{
+		{line: "Card 1 41 | 83", v: &card{}, column: 6},
+		{line: "Card x: 41 | 83", v: &card{}, column: 6},
+		{line: "Game 1: 1 blue, 2 purple", v: &game{}, column: 19},
+	}
+	for _, testCase := range testCases {
+		err := Unmarshal(testCase.line, testCase.v)
+		var parseErr *parse.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q: expected a ParseError, got %v", testCase.line, err)
+			continue
+		}
+		if parseErr.Column != testCase.column {
+			t.Errorf("%q: expected column %d, got %v", testCase.line, testCase.column, parseErr)
+		}
+	}
+
+	if err := Unmarshal("1", &struct{ A int }{}); err == nil {
+		t.Errorf("expected an error for a struct without a pattern")
+	}
+}
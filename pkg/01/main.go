@@ -1,20 +1,66 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
-	"regexp"
+	"maps"
+	"os"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/ahocorasick"
+	"github.com/max-nicholson/advent-of-code-2023/lib/parse"
 )
 
 func main() {
+	vocab := flag.String("vocab", "", "extra tokens as token=number entries separated by commas, e.g. zero=0,twelve=12")
+	vocabFile := flag.String("vocab-file", "", "a file of extra token=number entries, one per line")
+	explain := flag.String("explain", "", "explain how each line decodes for part1, part2 or custom, instead of solving")
+	format := flag.String("format", "text", "the --explain output format, text or json")
+	flag.Parse()
+
 	lines, err := lib.ReadLines("pkg/01/input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	custom := Digits
+	if *vocabFile != "" {
+		fromFile, err := LoadVocabulary(*vocabFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		custom = custom.Merge(fromFile)
+	}
+	if *vocab != "" {
+		fromFlag, err := ParseVocabulary(*vocab)
+		if err != nil {
+			log.Fatal(err)
+		}
+		custom = custom.Merge(fromFlag)
+	}
+
+	if *explain != "" {
+		vocabularies := map[string]Vocabulary{
+			"part1":  Digits,
+			"part2":  Digits.Merge(English),
+			"custom": custom,
+		}
+		vocabulary, ok := vocabularies[*explain]
+		if !ok {
+			log.Fatalf("unknown --explain %q, expected part1, part2 or custom", *explain)
+		}
+		if err := WriteExplanations(os.Stdout, NewDecoder(vocabulary).Explain(lines), *format); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	part1, err := Part1(lines)
 	if err != nil {
 		log.Fatal(err)
@@ -26,86 +72,215 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("part2: %v\n", part2)
+
+	if *vocab == "" && *vocabFile == "" {
+		return
+	}
+	total, err := NewDecoder(custom).Sum(lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("custom: %v\n", total)
 }
 
-var part1Regexp = regexp.MustCompile(`\d`)
+// Vocabulary maps each token to the number it spells. A token spelling a number of
+// more than one digit (e.g "twelve") gives its leading digit when it is the first
+// token on a line and its trailing digit when it is the last
+type Vocabulary map[string]int
 
-func Part1(lines []string) (int, error) {
-	total := 0
-	for _, line := range lines {
-		matches := part1Regexp.FindAllString(line, -1)
-		if matches == nil {
-			return 0, fmt.Errorf("no digits found in line: %s", line)
+// Digits are the numerals 0 to 9
+var Digits = Vocabulary{
+	"0": 0, "1": 1, "2": 2, "3": 3, "4": 4,
+	"5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
+}
+
+// English are the words one to nine
+var English = Vocabulary{
+	"one": 1, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9,
+}
+
+// Merge returns a Vocabulary with the tokens of v and others, later ones winning
+func (v Vocabulary) Merge(others ...Vocabulary) Vocabulary {
+	merged := maps.Clone(v)
+	for _, other := range others {
+		maps.Copy(merged, other)
+	}
+	return merged
+}
+
+// ParseVocabulary parses token=number entries separated by commas or newlines.
+// Blank entries and lines starting with # are ignored
+func ParseVocabulary(text string) (Vocabulary, error) {
+	vocabulary := Vocabulary{}
+	for _, entry := range parse.Split(text, ",", "\n") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
 		}
-		i, err := strconv.Atoi(matches[0] + matches[len(matches)-1])
-		if err != nil {
-			return 0, err
+		token, number, ok := strings.Cut(entry, "=")
+		token = strings.TrimSpace(token)
+		if !ok || token == "" {
+			return nil, fmt.Errorf("expected token=number, got %q", entry)
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(number))
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("expected a non-negative number for %q, got %q", token, number)
 		}
-		total += i
+		if existing, ok := vocabulary[token]; ok && existing != value {
+			return nil, fmt.Errorf("token %q is both %d and %d", token, existing, value)
+		}
+		vocabulary[token] = value
 	}
-	return total, nil
+	return vocabulary, nil
 }
 
-var part2ForwardRegexp = regexp.MustCompile(`\d|one|two|three|four|five|six|seven|eight|nine`)
-var part2BackwardRegexp = regexp.MustCompile(`\d|enin|thgie|neves|xis|evif|ruof|eerht|owt|eno`)
+// LoadVocabulary parses the vocabulary file at path, see ParseVocabulary
+func LoadVocabulary(path string) (Vocabulary, error) {
+	text, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vocabulary file: %w", err)
+	}
+	vocabulary, err := ParseVocabulary(string(text))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return vocabulary, nil
+}
 
-func Part2(lines []string) (int, error) {
-	total := 0
-	values := map[string]int{
-		"one":   1,
-		"two":   2,
-		"three": 3,
-		"four":  4,
-		"five":  5,
-		"six":   6,
-		"seven": 7,
-		"eight": 8,
-		"nine":  9,
+// Decoder finds calibration values using a single matcher over every token of a Vocabulary
+type Decoder struct {
+	matcher *ahocorasick.Matcher
+	values  []int
+}
+
+func NewDecoder(vocabulary Vocabulary) *Decoder {
+	tokens := make([]string, 0, len(vocabulary))
+	for token := range vocabulary {
+		tokens = append(tokens, token)
+	}
+	slices.Sort(tokens)
+	values := make([]int, len(tokens))
+	for i, token := range tokens {
+		values[i] = vocabulary[token]
 	}
+	return &Decoder{matcher: ahocorasick.New(tokens...), values: values}
+}
 
-	for _, line := range lines {
-		value := 0
-
-		// can't use one regexp, as Go's regex engine doesn't do overlapping matches
-		// e.g. "sevenine" with a single Regexp would return 77, instead of 79, due to the shared "n"
-		// hacky solution is to run a "forward" Regex, and a "backward" Regex on a reversed string
-		match := part2ForwardRegexp.FindString(line)
-		if match == "" {
-			return 0, fmt.Errorf("no digits found in line: %s", line)
-		}
-		if len(match) == 1 {
-			v, err := strconv.Atoi(match)
-			if err != nil {
-				return 0, fmt.Errorf("expected digit, got %s", match)
-			}
-			value += v * 10
+func leadingDigit(n int) int {
+	for n >= 10 {
+		n /= 10
+	}
+	return n
+}
+
+// Token is an occurrence of a vocabulary token at the byte offsets [Start, End) of a line
+type Token struct {
+	Text  string `json:"text"`
+	Value int    `json:"value"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+func (d *Decoder) token(line string, match ahocorasick.Match) Token {
+	return Token{Text: line[match.Start:match.End], Value: d.values[match.Pattern], Start: match.Start, End: match.End}
+}
+
+// Tokens returns the first and last token of line, which may be the same token
+func (d *Decoder) Tokens(line string) (first Token, last Token, err error) {
+	firstMatch, ok := d.matcher.First(line)
+	if !ok {
+		return Token{}, Token{}, fmt.Errorf("no digits found in line: %s", line)
+	}
+	lastMatch, _ := d.matcher.Last(line)
+	return d.token(line, firstMatch), d.token(line, lastMatch), nil
+}
+
+// Value combines the first and last token of line into a two-digit number.
+// Tokens may overlap, e.g "sevenine" is 79
+func (d *Decoder) Value(line string) (int, error) {
+	first, last, err := d.Tokens(line)
+	if err != nil {
+		return 0, err
+	}
+	return leadingDigit(first.Value)*10 + last.Value%10, nil
+}
+
+// Explanation shows how the calibration value of a line was decoded
+type Explanation struct {
+	// Line is 1-based
+	Line  int    `json:"line"`
+	Text  string `json:"text"`
+	First *Token `json:"first,omitempty"`
+	Last  *Token `json:"last,omitempty"`
+	Value int    `json:"value"`
+	Error string `json:"error,omitempty"`
+}
+
+// Explain decodes every line, recording lines that fail rather than stopping
+func (d *Decoder) Explain(lines []string) []Explanation {
+	explanations := make([]Explanation, len(lines))
+	for i, line := range lines {
+		explanation := Explanation{Line: i + 1, Text: line}
+		first, last, err := d.Tokens(line)
+		if err != nil {
+			explanation.Error = err.Error()
 		} else {
-			v, ok := values[match]
-			if !ok {
-				return 0, fmt.Errorf("expected %s to map to a digit", match)
-			}
-			value += v * 10
+			explanation.First, explanation.Last = &first, &last
+			explanation.Value = leadingDigit(first.Value)*10 + last.Value%10
 		}
+		explanations[i] = explanation
+	}
+	return explanations
+}
 
-		match = part2BackwardRegexp.FindString(lib.Reverse(line))
-		if match == "" {
-			return 0, fmt.Errorf("no digits found in line: %s", line)
-		}
-		if len(match) == 1 {
-			v, err := strconv.Atoi(match)
-			if err != nil {
-				return 0, fmt.Errorf("expected digit, got %s", match)
+// WriteExplanations writes explanations to w as "text", one line each followed by
+// the total, or as a "json" array
+func WriteExplanations(w io.Writer, explanations []Explanation, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(explanations)
+	case "text":
+		total := 0
+		for _, e := range explanations {
+			var err error
+			if e.Error != "" {
+				_, err = fmt.Fprintf(w, "%d: %s: error: %s\n", e.Line, e.Text, e.Error)
+			} else {
+				total += e.Value
+				_, err = fmt.Fprintf(w, "%d: %s: first %q [%d:%d] last %q [%d:%d] = %02d\n",
+					e.Line, e.Text, e.First.Text, e.First.Start, e.First.End, e.Last.Text, e.Last.Start, e.Last.End, e.Value)
 			}
-			value += v
-		} else {
-			v, ok := values[lib.Reverse(match)]
-			if !ok {
-				return 0, fmt.Errorf("expected %s to map to a digit", match)
+			if err != nil {
+				return err
 			}
-			value += v
 		}
+		_, err := fmt.Fprintf(w, "total: %d\n", total)
+		return err
+	default:
+		return fmt.Errorf("unknown format %q, expected text or json", format)
+	}
+}
 
+// Sum adds up the calibration value of each line
+func (d *Decoder) Sum(lines []string) (int, error) {
+	total := 0
+	for _, line := range lines {
+		value, err := d.Value(line)
+		if err != nil {
+			return 0, err
+		}
 		total += value
 	}
 	return total, nil
 }
+
+func Part1(lines []string) (int, error) {
+	return NewDecoder(Digits).Sum(lines)
+}
+
+func Part2(lines []string) (int, error) {
+	return NewDecoder(Digits.Merge(English)).Sum(lines)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"maps"
 	"strings"
 	"testing"
 )
@@ -43,3 +44,96 @@ func TestPart2PartialNumber(t *testing.T) {
 		t.Fatalf("want 31, got %d", total)
 	}
 }
+
+func TestDecoderValue(t *testing.T) {
+	var testCases = []struct {
+		vocabulary Vocabulary
+		line       string
+		want       int
+	}{
+		{vocabulary: Digits.Merge(English), line: "sevenine", want: 79},
+		{vocabulary: Digits.Merge(English), line: "oneight", want: 18},
+		{vocabulary: Digits, line: "a5b", want: 55},
+		{vocabulary: Digits.Merge(Vocabulary{"zero": 0}), line: "zero3", want: 3},
+		// multi-digit tokens give their leading digit first and trailing digit last
+		{vocabulary: Vocabulary{"twelve": 12, "three": 3}, line: "twelvethree", want: 13},
+		{vocabulary: Vocabulary{"twelve": 12, "three": 3}, line: "threetwelve", want: 32},
+		{vocabulary: Vocabulary{"uno": 1, "dos": 2, "tres": 3}, line: "xdosunotresx", want: 23},
+		{vocabulary: Vocabulary{"first": 1, "second": 2}, line: "secondfirst", want: 21},
+	}
+	for _, testCase := range testCases {
+		result, err := NewDecoder(testCase.vocabulary).Value(testCase.line)
+		if err != nil {
+			t.Error(err)
+		}
+		if result != testCase.want {
+			t.Errorf("%s: expected %d, got %d", testCase.line, testCase.want, result)
+		}
+	}
+}
+
+func TestParseVocabulary(t *testing.T) {
+	result, err := ParseVocabulary("# extras\nzero=0, twelve = 12\n\nfirst=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Vocabulary{"zero": 0, "twelve": 12, "first": 1}
+	if !maps.Equal(result, want) {
+		t.Fatalf("expected %v, got %v", want, result)
+	}
+
+	for _, text := range []string{"one", "=1", "one=x", "one=-1", "one=1,one=2"} {
+		if _, err := ParseVocabulary(text); err == nil {
+			t.Fatalf("%q: expected an error", text)
+		}
+	}
+}
+
+func TestWriteExplanations(t *testing.T) {
+	explanations := NewDecoder(Digits.Merge(English)).Explain([]string{"two1nine", "sevenine", "abc"})
+
+	var sb strings.Builder
+	if err := WriteExplanations(&sb, explanations, "text"); err != nil {
+		t.Fatal(err)
+	}
+	want := `1: two1nine: first "two" [0:3] last "nine" [4:8] = 29
+2: sevenine: first "seven" [0:5] last "nine" [4:8] = 79
+3: abc: error: no digits found in line: abc
+total: 108
+`
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+
+	sb.Reset()
+	if err := WriteExplanations(&sb, explanations[1:2], "json"); err != nil {
+		t.Fatal(err)
+	}
+	want = `[
+  {
+    "line": 2,
+    "text": "sevenine",
+    "first": {
+      "text": "seven",
+      "value": 7,
+      "start": 0,
+      "end": 5
+    },
+    "last": {
+      "text": "nine",
+      "value": 9,
+      "start": 4,
+      "end": 8
+    },
+    "value": 79
+  }
+]
+`
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+
+	if err := WriteExplanations(&sb, explanations, "xml"); err == nil {
+		t.Fatalf("expected an error for an unknown format")
+	}
+}
@@ -1,140 +1,419 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
-	"strconv"
+	"maps"
+	"os"
+	"slices"
 	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/parse"
 )
 
 func main() {
+	bagText := flag.String("bag", "", `the bag contents for part 1, e.g. "12 red, 13 green, 14 blue"`)
+	bagFile := flag.String("bag-file", "", `a JSON file of the bag contents for part 1, e.g. {"red": 12, "purple": 3}`)
+	strict := flag.Bool("strict", false, "fail on games drawing a colour the bag doesn't hold")
+	infer := flag.Bool("infer", false, "print the smallest bag every game could have been played with")
+	candidates := flag.String("candidates", "", "a JSON file of an array of bags, to print the games possible with each")
+	budget := flag.Int("budget", -1, "print the bag of at most this many cubes that explains the most games")
+	flag.Parse()
+
 	lines, err := lib.ReadLines("pkg/02/input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	part1, err := Part1(lines)
+	bag := totalCubesByColour
+	switch {
+	case *bagText != "" && *bagFile != "":
+		log.Fatal("expected at most one of --bag and --bag-file")
+	case *bagText != "":
+		bag, err = ParseBag(*bagText)
+	case *bagFile != "":
+		bag, err = LoadBag(*bagFile)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("part1: %v\n", part1)
 
-	part2, err := Part2(lines)
+	games, err := ParseGames(lines, bag, *strict)
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("part2: %v\n", part2)
+
+	fmt.Printf("part1: %v\n", SumPossible(games, bag))
+	fmt.Printf("part2: %v\n", SumPowers(games))
+
+	if *infer {
+		fmt.Printf("minimal bag: %s\n", MinimalBag(games))
+	}
+	if *candidates != "" {
+		bags, err := LoadBags(*candidates)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for i, possible := range PossibleGames(games, bags) {
+			fmt.Printf("bag %s: %d games %v\n", bags[i], len(possible), gameIDs(possible))
+		}
+	}
+	if *budget >= 0 {
+		bag, explained := LargestExplainable(games, *budget)
+		fmt.Printf("budget %d: bag %s (%d cubes) explains %d games %v\n", *budget, bag, bag.Total(), len(explained), gameIDs(explained))
+	}
+}
+
+type Colour string
+
+// Bag is a number of cubes of each colour
+type Bag map[Colour]int
+
+// Colours are the colours of cube in the puzzle's bag
+var Colours = []Colour{"red", "green", "blue"}
+
+var totalCubesByColour = Bag{"red": 12, "green": 13, "blue": 14}
+
+var ErrUnknownColour = errors.New("unknown colour")
+
+type bagRecord struct {
+	_     struct{}     `aoc:"{Cubes}"`
+	Cubes []cubeRecord `sep:","`
+}
+
+// ParseBag parses the contents of a bag written like a draw, e.g "12 red, 13 green, 14 blue"
+func ParseBag(text string) (Bag, error) {
+	var record bagRecord
+	if err := lib.Unmarshal(text, &record); err != nil {
+		return nil, err
+	}
+	bag := Bag{}
+	for _, cube := range record.Cubes {
+		if _, ok := bag[cube.Colour]; ok {
+			return nil, fmt.Errorf("%s given twice", cube.Colour)
+		}
+		bag[cube.Colour] = cube.Count
+	}
+	return bag, bag.validate()
 }
 
-var totalCubesByColour = map[string]int{"red": 12, "green": 13, "blue": 14}
+// LoadBag reads a bag from a JSON object of colour to count, e.g {"red": 12, "purple": 3}
+func LoadBag(path string) (Bag, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bag file: %w", err)
+	}
+	var bag Bag
+	if err := json.Unmarshal(data, &bag); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := bag.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return bag, nil
+}
 
-func IsPossibleGame(line string) (bool, error) {
-	slices := strings.Split(line, ": ")
-	if slices == nil || len(slices) != 2 {
-		return false, fmt.Errorf("expected line %s to split by ':'", line)
+// LoadBags reads a JSON array of bags, see LoadBag
+func LoadBags(path string) ([]Bag, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bags file: %w", err)
 	}
-	sets := strings.Split(slices[1], "; ")
-	if len(sets) == 0 {
-		return false, fmt.Errorf("no game information found for line %s", slices[1])
+	var bags []Bag
+	if err := json.Unmarshal(data, &bags); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
 	}
-	for _, set := range sets {
-		cubes := strings.Split(set, ", ")
-		if len(cubes) == 0 {
-			return false, fmt.Errorf("no sets found for line %s", slices[1])
+	for i, bag := range bags {
+		if err := bag.validate(); err != nil {
+			return nil, fmt.Errorf("%s: bag %d: %w", path, i+1, err)
 		}
+	}
+	return bags, nil
+}
+
+func (b Bag) validate() error {
+	if len(b) == 0 {
+		return fmt.Errorf("bag has no colours")
+	}
+	for colour, count := range b {
+		if count < 0 {
+			return fmt.Errorf("expected a non-negative number of %s cubes, got %d", colour, count)
+		}
+	}
+	return nil
+}
+
+// Power multiplies together the number of cubes of each of colours, counting
+// colours missing from b as 0
+func (b Bag) Power(colours ...Colour) int {
+	power := 1
+	for _, colour := range colours {
+		power *= b[colour]
+	}
+	return power
+}
+
+type Game struct {
+	ID    int
+	Draws []map[Colour]int
+}
+
+type cubeRecord struct {
+	_      struct{} `aoc:"{Count} {Colour}"`
+	Count  int
+	Colour Colour
+}
+
+type gameRecord struct {
+	_     struct{} `aoc:"Game {ID}: {Draws}"`
+	ID    int
+	Draws [][]cubeRecord `sep:"; " sep2:", "`
+}
+
+func ParseGame(line string) (*Game, error) {
+	var record gameRecord
+	if err := lib.Unmarshal(line, &record); err != nil {
+		return nil, err
+	}
+	game := &Game{ID: record.ID, Draws: make([]map[Colour]int, len(record.Draws))}
+	for i, cubes := range record.Draws {
+		draw := make(map[Colour]int, len(cubes))
 		for _, cube := range cubes {
-			record := strings.Split(cube, " ")
-			if len(record) != 2 {
-				return false, fmt.Errorf("expected cube data to have format [1-9]+ blue|green|red")
-			}
-			count, err := strconv.Atoi(record[0])
-			if err != nil {
-				return false, fmt.Errorf("unable to parse number of cubes: %w", err)
+			if _, ok := draw[cube.Colour]; ok {
+				return nil, fmt.Errorf("game %d: %s drawn twice in draw %d", record.ID, cube.Colour, i+1)
 			}
-			colour := record[1]
-			max, ok := totalCubesByColour[colour]
-			if !ok {
-				log.Printf("got unexpected colour %s", colour)
-				continue
+			draw[cube.Colour] = cube.Count
+		}
+		game.Draws[i] = draw
+	}
+	return game, nil
+}
+
+// ParseGames parses a game per line, logging any game that draws a colour bag doesn't
+// hold. In strict mode that is an error instead
+func ParseGames(lines []string, bag Bag, strict bool) ([]*Game, error) {
+	return parse.Each(lines, func(line string) (*Game, error) {
+		game, err := ParseGame(line)
+		if err != nil {
+			return nil, err
+		}
+		if err := game.CheckColours(bag); err != nil {
+			if strict {
+				return nil, err
 			}
-			if count > max {
-				return false, nil
+			log.Printf("got unexpected colour: %v", err)
+		}
+		return game, nil
+	})
+}
+
+// CheckColours returns ErrUnknownColour if any draw has a colour bag doesn't hold
+func (g *Game) CheckColours(bag Bag) error {
+	for i, draw := range g.Draws {
+		for colour := range draw {
+			if _, ok := bag[colour]; !ok {
+				return fmt.Errorf("game %d draw %d: %w %s", g.ID, i+1, ErrUnknownColour, colour)
 			}
 		}
 	}
-	return true, nil
+	return nil
 }
 
-func Part1(lines []string) (int, error) {
-	total := 0
-	for i, line := range lines {
-		isPossible, err := IsPossibleGame(line)
-		if err != nil {
-			return 0, err
+// Max is the most cubes of each colour seen in any one draw, i.e. the smallest bag
+// the game could have been played with
+func (g *Game) Max() Bag {
+	maxima := Bag{}
+	for _, draw := range g.Draws {
+		for colour, count := range draw {
+			if count > maxima[colour] {
+				maxima[colour] = count
+			}
 		}
-		if isPossible {
-			total += i + 1
+	}
+	return maxima
+}
+
+// IsPossible reports whether every draw could have come from bag. Colours the bag
+// doesn't list count as 0
+func (g *Game) IsPossible(bag Bag) bool {
+	return bag.Covers(g.Max())
+}
+
+// Power is the power of the smallest bag the game could have been played with
+func (g *Game) Power() int {
+	return g.Max().Power(Colours...)
+}
+
+// Covers reports whether b holds at least as many cubes of each colour as other,
+// counting colours b doesn't hold as 0
+func (b Bag) Covers(other Bag) bool {
+	for colour, count := range other {
+		if count > b[colour] {
+			return false
 		}
 	}
-	return total, nil
+	return true
 }
 
-func PowerOfSet(line string) (int, error) {
-	slices := strings.Split(line, ": ")
-	if slices == nil || len(slices) != 2 {
-		return 0, fmt.Errorf("expected line %s to split by ':'", line)
+// Total is the number of cubes in b
+func (b Bag) Total() int {
+	total := 0
+	for _, count := range b {
+		total += count
 	}
-	sets := strings.Split(slices[1], "; ")
-	if len(sets) == 0 {
-		return 0, fmt.Errorf("no game information found for line %s", slices[1])
+	return total
+}
+
+// String writes b like ParseBag expects, with colours in alphabetical order
+func (b Bag) String() string {
+	colours := make([]string, 0, len(b))
+	for colour := range b {
+		colours = append(colours, string(colour))
 	}
-	minimumSet := map[string]int{
-		"red":   0,
-		"blue":  0,
-		"green": 0,
+	slices.Sort(colours)
+	cubes := make([]string, len(colours))
+	for i, colour := range colours {
+		cubes[i] = fmt.Sprintf("%d %s", b[Colour(colour)], colour)
 	}
-	for _, set := range sets {
-		cubes := strings.Split(set, ", ")
-		if len(cubes) == 0 {
-			return 0, fmt.Errorf("no sets found for line %s", slices[1])
+	return strings.Join(cubes, ", ")
+}
+
+// MinimalBag is the smallest bag every game could have been played with
+func MinimalBag(games []*Game) Bag {
+	bag := Bag{}
+	for _, game := range games {
+		for colour, count := range game.Max() {
+			bag[colour] = max(bag[colour], count)
 		}
-		for _, cube := range cubes {
-			record := strings.Split(cube, " ")
-			if len(record) != 2 {
-				return 0, fmt.Errorf("expected cube data to have format [1-9]+ blue|green|red")
-			}
-			count, err := strconv.Atoi(record[0])
-			if err != nil {
-				return 0, fmt.Errorf("unable to parse number of cubes: %w", err)
+	}
+	return bag
+}
+
+// PossibleGames returns, for each of bags, the games that could have been played
+// with it
+func PossibleGames(games []*Game, bags []Bag) [][]*Game {
+	possible := make([][]*Game, len(bags))
+	for i, bag := range bags {
+		possible[i] = []*Game{}
+		for _, game := range games {
+			if game.IsPossible(bag) {
+				possible[i] = append(possible[i], game)
 			}
-			colour := record[1]
-			min, ok := minimumSet[colour]
-			if !ok {
-				log.Printf("got unexpected colour %s", colour)
-				continue
+		}
+	}
+	return possible
+}
+
+// LargestExplainable finds a bag of at most budget cubes that explains as many games
+// as possible, returning the smallest such bag and the games it explains.
+//
+// The best bag only ever needs as many cubes of a colour as some game's maximum, so
+// this searches those thresholds colour by colour, giving up on a branch once it is
+// over budget or can no longer beat the best found. That is exponential in the
+// number of colours, but fine for a handful
+func LargestExplainable(games []*Game, budget int) (Bag, []*Game) {
+	maxima := make([]Bag, len(games))
+	thresholds := map[Colour][]int{}
+	for i, game := range games {
+		maxima[i] = game.Max()
+		for colour, count := range maxima[i] {
+			thresholds[colour] = append(thresholds[colour], count)
+		}
+	}
+	colours := make([]Colour, 0, len(thresholds))
+	for colour, counts := range thresholds {
+		colours = append(colours, colour)
+		slices.Sort(counts)
+		thresholds[colour] = slices.Compact(append([]int{0}, counts...))
+	}
+	slices.Sort(colours)
+
+	var best []int
+	bestTotal := 0
+	bestBag, bag := Bag{}, Bag{}
+	var search func(depth int, total int, candidates []int)
+	search = func(depth int, total int, candidates []int) {
+		if len(candidates) < len(best) || (len(candidates) == len(best) && total >= bestTotal) {
+			// adding colours only removes candidates and adds cubes
+			return
+		}
+		if depth == len(colours) {
+			best, bestTotal, bestBag = candidates, total, maps.Clone(bag)
+			return
+		}
+		colour := colours[depth]
+		for _, count := range thresholds[colour] {
+			if total+count > budget {
+				break
 			}
-			if count > min {
-				minimumSet[colour] = count
+			remaining := []int{}
+			for _, i := range candidates {
+				if maxima[i][colour] <= count {
+					remaining = append(remaining, i)
+				}
 			}
+			bag[colour] = count
+			search(depth+1, total+count, remaining)
 		}
+		delete(bag, colour)
 	}
-	power := 1
-	for _, val := range minimumSet {
-		power *= val
+
+	all := make([]int, len(games))
+	for i := range games {
+		all[i] = i
+	}
+	search(0, 0, all)
+
+	explained := make([]*Game, len(best))
+	for i, index := range best {
+		explained[i] = games[index]
 	}
-	return power, nil
+	return bestBag, explained
 }
 
-func Part2(lines []string) (int, error) {
+// SumPossible adds up the IDs of the games possible with bag
+func SumPossible(games []*Game, bag Bag) int {
 	total := 0
-	for _, line := range lines {
-		power, err := PowerOfSet(line)
-		if err != nil {
-			return 0, err
+	for _, game := range games {
+		if game.IsPossible(bag) {
+			total += game.ID
 		}
-		total += power
 	}
-	return total, nil
+	return total
+}
+
+func gameIDs(games []*Game) []int {
+	ids := make([]int, len(games))
+	for i, game := range games {
+		ids[i] = game.ID
+	}
+	return ids
+}
+
+func SumPowers(games []*Game) int {
+	total := 0
+	for _, game := range games {
+		total += game.Power()
+	}
+	return total
+}
+
+func Part1(lines []string) (int, error) {
+	games, err := ParseGames(lines, totalCubesByColour, false)
+	if err != nil {
+		return 0, err
+	}
+	return SumPossible(games, totalCubesByColour), nil
+}
+
+func Part2(lines []string) (int, error) {
+	games, err := ParseGames(lines, totalCubesByColour, false)
+	if err != nil {
+		return 0, err
+	}
+	return SumPowers(games), nil
 }
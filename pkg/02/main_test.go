@@ -1,6 +1,11 @@
 package main
 
 import (
+	"errors"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 )
@@ -32,3 +37,177 @@ Game 5: 6 red, 1 blue, 3 green; 2 blue, 1 red, 2 green`, "\n"))
 		t.Fatalf("expected 2286, got %d", total)
 	}
 }
+
+func TestParseGame(t *testing.T) {
+	game, err := ParseGame("Game 12: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if game.ID != 12 {
+		t.Fatalf("expected ID 12, got %d", game.ID)
+	}
+	if len(game.Draws) != 3 {
+		t.Fatalf("expected 3 draws, got %d", len(game.Draws))
+	}
+	if want := map[Colour]int{"red": 1, "green": 2, "blue": 6}; !maps.Equal(game.Draws[1], want) {
+		t.Fatalf("expected %v, got %v", want, game.Draws[1])
+	}
+	if want := (Bag{"red": 4, "green": 2, "blue": 6}); !maps.Equal(game.Max(), want) {
+		t.Fatalf("expected %v, got %v", want, game.Max())
+	}
+	if game.Power() != 48 {
+		t.Fatalf("expected power 48, got %d", game.Power())
+	}
+
+	for _, line := range []string{
+		"Game x: 1 red",
+		"Game 1 1 red",
+		"Game 1: red",
+		"Game 1: 1 red, 2 red",
+	} {
+		if _, err := ParseGame(line); err == nil {
+			t.Fatalf("%q: expected an error", line)
+		}
+	}
+}
+
+func TestSumPossibleUsesGameIDs(t *testing.T) {
+	games, err := ParseGames([]string{
+		"Game 7: 1 red",
+		"Game 3: 20 red",
+		"Game 10: 12 red, 13 green, 14 blue",
+	}, totalCubesByColour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := SumPossible(games, totalCubesByColour); total != 17 {
+		t.Fatalf("expected 17, got %d", total)
+	}
+}
+
+func TestParseBag(t *testing.T) {
+	bag, err := ParseBag("2 purple,  12 red, 0 gold")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Bag{"purple": 2, "red": 12, "gold": 0}); !maps.Equal(bag, want) {
+		t.Fatalf("expected %v, got %v", want, bag)
+	}
+
+	for _, text := range []string{"", "red", "1 red, 2 red", "-1 red"} {
+		if _, err := ParseBag(text); err == nil {
+			t.Fatalf("%q: expected an error", text)
+		}
+	}
+}
+
+func TestLoadBag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bag.json")
+	if err := os.WriteFile(path, []byte(`{"purple": 3, "red": 1}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	bag, err := LoadBag(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Bag{"purple": 3, "red": 1}); !maps.Equal(bag, want) {
+		t.Fatalf("expected %v, got %v", want, bag)
+	}
+}
+
+func TestStrictColours(t *testing.T) {
+	lines := []string{
+		"Game 1: 1 red, 2 purple",
+		"Game 2: 2 purple; 1 gold",
+		"Game 3: 50 blue",
+	}
+	bag := Bag{"red": 1, "purple": 2}
+
+	games, err := ParseGames(lines, bag, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// gold and blue aren't in the bag, so any drawn makes a game impossible
+	if total := SumPossible(games, bag); total != 1 {
+		t.Fatalf("expected 1, got %d", total)
+	}
+
+	_, err = ParseGames(lines, bag, true)
+	if !errors.Is(err, ErrUnknownColour) {
+		t.Fatalf("expected ErrUnknownColour, got %v", err)
+	}
+	if !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Fatalf("expected the error to give the line, got %v", err)
+	}
+}
+
+var example = strings.Split(`Game 1: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green
+Game 2: 1 blue, 2 green; 3 green, 4 blue, 1 red; 1 green, 1 blue
+Game 3: 8 green, 6 blue, 20 red; 5 blue, 4 red, 13 green; 5 green, 1 red
+Game 4: 1 green, 3 red, 6 blue; 3 green, 6 red; 3 green, 15 blue, 14 red
+Game 5: 6 red, 1 blue, 3 green; 2 blue, 1 red, 2 green`, "\n")
+
+func TestMinimalBag(t *testing.T) {
+	games, err := ParseGames(example, totalCubesByColour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bag := MinimalBag(games)
+	want := Bag{"red": 20, "green": 13, "blue": 15}
+	if !maps.Equal(bag, want) {
+		t.Fatalf("expected %v, got %v", want, bag)
+	}
+	if bag.String() != "15 blue, 13 green, 20 red" {
+		t.Fatalf("expected 15 blue, 13 green, 20 red, got %s", bag)
+	}
+	if possible := PossibleGames(games, []Bag{bag}); len(possible[0]) != len(games) {
+		t.Fatalf("expected the minimal bag to explain every game, got %d", len(possible[0]))
+	}
+}
+
+func TestPossibleGames(t *testing.T) {
+	games, err := ParseGames(example, totalCubesByColour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	possible := PossibleGames(games, []Bag{
+		totalCubesByColour,
+		{"red": 6, "green": 3, "blue": 6},
+		{"red": 100},
+	})
+	want := [][]int{{1, 2, 5}, {1, 2, 5}, {}}
+	for i := range want {
+		if got := gameIDs(possible[i]); !slices.Equal(got, want[i]) {
+			t.Fatalf("bag %d: expected %v, got %v", i, want[i], got)
+		}
+	}
+}
+
+func TestLargestExplainable(t *testing.T) {
+	games, err := ParseGames(example, totalCubesByColour, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var testCases = []struct {
+		budget int
+		bag    Bag
+		ids    []int
+	}{
+		{budget: 0, bag: Bag{}, ids: []int{}},
+		{budget: 14, bag: Bag{"blue": 4, "green": 3, "red": 6}, ids: []int{2, 5}},
+		{budget: 15, bag: Bag{"blue": 6, "green": 3, "red": 6}, ids: []int{1, 2, 5}},
+		{budget: 31, bag: Bag{"blue": 6, "green": 3, "red": 6}, ids: []int{1, 2, 5}},
+		{budget: 32, bag: Bag{"blue": 15, "green": 3, "red": 14}, ids: []int{1, 2, 4, 5}},
+		{budget: 48, bag: Bag{"blue": 15, "green": 13, "red": 20}, ids: []int{1, 2, 3, 4, 5}},
+	}
+	for _, testCase := range testCases {
+		bag, explained := LargestExplainable(games, testCase.budget)
+		if !maps.Equal(bag, testCase.bag) {
+			t.Errorf("budget %d: expected bag %v, got %v", testCase.budget, testCase.bag, bag)
+		}
+		if ids := gameIDs(explained); !slices.Equal(ids, testCase.ids) {
+			t.Errorf("budget %d: expected games %v, got %v", testCase.budget, testCase.ids, ids)
+		}
+	}
+}
@@ -1,15 +1,25 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
+	"math"
+	"slices"
 	"strconv"
+	"strings"
 	"unicode"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
 )
 
 func main() {
+	symbols := flag.String("gear-symbols", "*", "the symbols that can be gears, or any symbol if empty")
+	count := flag.String("gear-count", "2", "how many numbers a gear touches: N, >=N or N-M")
+	aggregate := flag.String("gear-aggregate", "product", "how a gear's numbers make its ratio: product, sum or max")
+	neighbourhood := flag.Int("gear-neighbours", 8, "4 to only count numbers orthogonally next to a gear, or 8 to include diagonals")
+	flag.Parse()
+
 	lines, err := lib.ReadLines("pkg/03/input.txt")
 	if err != nil {
 		log.Fatal(err)
@@ -26,148 +36,320 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("part2: %v\n", part2)
+
+	custom := false
+	flag.Visit(func(f *flag.Flag) {
+		custom = custom || strings.HasPrefix(f.Name, "gear-")
+	})
+	if !custom {
+		return
+	}
+	rule := GearRule{Symbols: *symbols}
+	if rule.Count, err = ParseCount(*count); err != nil {
+		log.Fatal(err)
+	}
+	if rule.Aggregate, err = ParseAggregation(*aggregate); err != nil {
+		log.Fatal(err)
+	}
+	switch *neighbourhood {
+	case 4:
+		rule.Neighbours = lib.Neighbours4
+	case 8:
+		rule.Neighbours = lib.Neighbours8
+	default:
+		log.Fatalf("expected a neighbourhood of 4 or 8, got %d", *neighbourhood)
+	}
+	schematic, err := ParseSchematic(lines)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("custom: %v\n", schematic.SumRatios(rule))
 }
 
-type Point struct {
-	r int
-	c int
+func isSymbol(char rune) bool {
+	return char != '.' && (unicode.IsPunct(char) || unicode.IsSymbol(char))
 }
 
-var neighbours = []Point{
-	{r: -1, c: -1},
-	{r: -1, c: 0},
-	{r: -1, c: 1},
-	{r: 0, c: -1},
-	{r: 0, c: 0},
-	{r: 0, c: 1},
-	{r: 1, c: -1},
-	{r: 1, c: 0},
-	{r: 1, c: 1},
+// Number is a horizontal run of digits, spanning columns [Start, End) of Row
+type Number struct {
+	ID    int
+	Value int
+	Row   int
+	Start int
+	End   int
 }
 
-func Part1(lines []string) (int, error) {
-	total := 0
-	seen := map[Point]int{}
-	maxR := len(lines) - 1
-	maxC := len(lines[0]) - 1
+type Symbol struct {
+	ID       int
+	Rune     rune
+	Position lib.Cell
+}
+
+// Schematic is every number and symbol of an engine schematic, indexed by which
+// touch each other (including diagonally)
+type Schematic struct {
+	Numbers []Number
+	Symbols []Symbol
+	// labels maps each cell to the ID of the number covering it, if any
+	labels *lib.Labels
+	// numberSymbols and symbolNumbers are the adjacency index, by ID
+	numberSymbols [][]int
+	symbolNumbers [][]int
+}
+
+func ParseSchematic(lines []string) (*Schematic, error) {
+	labels := lib.Label(len(lines), len(lines[0]), lib.FillRules{
+		Neighbours: []lib.Cell{{Column: -1}, {Column: 1}},
+		Passable: func(c lib.Cell) bool {
+			return unicode.IsDigit(rune(lines[c.Row][c.Column]))
+		},
+	})
+
+	s := &Schematic{
+		Numbers:       make([]Number, len(labels.Regions)),
+		labels:        labels,
+		numberSymbols: make([][]int, len(labels.Regions)),
+	}
+	for i, region := range labels.Regions {
+		text := lines[region.Min.Row][region.Min.Column : region.Max.Column+1]
+		value, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, fmt.Errorf("expected %s to be an integer: %w", text, err)
+		}
+		s.Numbers[i] = Number{ID: i, Value: value, Row: region.Min.Row, Start: region.Min.Column, End: region.Max.Column + 1}
+	}
+
 	for r, line := range lines {
-		chars := []rune(line)
-		for c, char := range chars {
-			point := Point{r, c}
-			if char == '.' || !(unicode.IsPunct(char) || unicode.IsSymbol(char)) {
+		for c, char := range line {
+			if !isSymbol(char) {
 				continue
 			}
-
-			for _, direction := range neighbours {
-				candidate := Point{r: direction.r + point.r, c: direction.c + point.c}
-				if candidate.r < 0 || candidate.c < 0 || candidate.r > maxR || candidate.c > maxC {
-					continue
-				}
-				_, ok := seen[candidate]
-				if ok {
-					continue
-				}
-				b := lines[candidate.r][candidate.c]
-				if !unicode.IsDigit(rune(b)) {
-					continue
-				}
-
-				seen[candidate] = 1
-				start := candidate.c
-				end := candidate.c
-				for i := candidate.c - 1; i >= 0; i-- {
-					char := lines[candidate.r][i]
-					if !unicode.IsDigit(rune(char)) {
-						break
-					}
-					start = i
-					seen[Point{r: candidate.r, c: i}] = 1
-				}
-				for i := candidate.c + 1; i <= maxC; i++ {
-					char := lines[candidate.r][i]
-					if !unicode.IsDigit(rune(char)) {
-						break
-					}
-					end = i
-					seen[Point{r: candidate.r, c: i}] = 1
-				}
-				partNumber, err := strconv.Atoi(lines[candidate.r][start : end+1])
-				if err != nil {
-					return 0, fmt.Errorf("expected %s to be an integer: %w", lines[r][start:end], err)
-				}
-				total += partNumber
+			symbol := Symbol{ID: len(s.Symbols), Rune: char, Position: lib.Cell{Row: r, Column: c}}
+			numbers := s.adjacent(symbol.Position, lib.Neighbours8)
+			for _, id := range numbers {
+				s.numberSymbols[id] = append(s.numberSymbols[id], symbol.ID)
 			}
+			s.Symbols = append(s.Symbols, symbol)
+			s.symbolNumbers = append(s.symbolNumbers, numbers)
 		}
 	}
+	return s, nil
+}
 
-	return total, nil
+// adjacent returns the IDs of the distinct numbers at any of neighbours from cell
+func (s *Schematic) adjacent(cell lib.Cell, neighbours []lib.Cell) []int {
+	ids := []int{}
+	for _, offset := range neighbours {
+		id := s.labels.At(cell.Add(offset))
+		if id != -1 && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
 }
 
-func Part2(lines []string) (int, error) {
+// NumbersOf returns the numbers touching symbol
+func (s *Schematic) NumbersOf(symbol Symbol) []Number {
+	numbers := make([]Number, len(s.symbolNumbers[symbol.ID]))
+	for i, id := range s.symbolNumbers[symbol.ID] {
+		numbers[i] = s.Numbers[id]
+	}
+	return numbers
+}
+
+// SymbolsOf returns the symbols touching number
+func (s *Schematic) SymbolsOf(number Number) []Symbol {
+	symbols := make([]Symbol, len(s.numberSymbols[number.ID]))
+	for i, id := range s.numberSymbols[number.ID] {
+		symbols[i] = s.Symbols[id]
+	}
+	return symbols
+}
+
+// IsPart reports whether number touches any symbol
+func (s *Schematic) IsPart(number Number) bool {
+	return len(s.numberSymbols[number.ID]) > 0
+}
+
+// Parts returns the numbers touching any symbol, in reading order
+func (s *Schematic) Parts() []Number {
+	return slices.DeleteFunc(slices.Clone(s.Numbers), func(n Number) bool {
+		return !s.IsPart(n)
+	})
+}
+
+// Orphans returns the numbers touching no symbol, in reading order
+func (s *Schematic) Orphans() []Number {
+	return slices.DeleteFunc(slices.Clone(s.Numbers), s.IsPart)
+}
+
+// PartsTouching returns the numbers touching any symbol drawn as r, in reading order
+func (s *Schematic) PartsTouching(r rune) []Number {
+	return slices.DeleteFunc(slices.Clone(s.Numbers), func(n Number) bool {
+		return !slices.ContainsFunc(s.SymbolsOf(n), func(symbol Symbol) bool {
+			return symbol.Rune == r
+		})
+	})
+}
+
+// SymbolsWithParts returns the symbols touching exactly n numbers
+func (s *Schematic) SymbolsWithParts(n int) []Symbol {
+	return slices.DeleteFunc(slices.Clone(s.Symbols), func(symbol Symbol) bool {
+		return len(s.symbolNumbers[symbol.ID]) != n
+	})
+}
+
+func Part1(lines []string) (int, error) {
+	schematic, err := ParseSchematic(lines)
+	if err != nil {
+		return 0, err
+	}
+
 	total := 0
-	maxR := len(lines) - 1
-	maxC := len(lines[0]) - 1
-	for r, line := range lines {
-		chars := []rune(line)
-		for c, char := range chars {
-			point := Point{r, c}
-			if char != '*' {
-				continue
-			}
+	for _, part := range schematic.Parts() {
+		total += part.Value
+	}
+	return total, nil
+}
 
-			partNumbers := []int{}
-			seen := map[Point]int{}
-			for _, direction := range neighbours {
-				candidate := Point{r: direction.r + point.r, c: direction.c + point.c}
-				if candidate.r < 0 || candidate.c < 0 || candidate.r > maxR || candidate.c > maxC {
-					continue
-				}
-				_, ok := seen[candidate]
-				if ok {
-					continue
-				}
-				b := lines[candidate.r][candidate.c]
-				if !unicode.IsDigit(rune(b)) {
-					continue
-				}
-
-				seen[candidate] = 1
-
-				if len(partNumbers) >= 2 {
-					break
-				}
-
-				start := candidate.c
-				end := candidate.c
-				for i := candidate.c - 1; i >= 0; i-- {
-					char := lines[candidate.r][i]
-					if !unicode.IsDigit(rune(char)) {
-						break
-					}
-					start = i
-					seen[Point{r: candidate.r, c: i}] = 1
-				}
-				for i := candidate.c + 1; i <= maxC; i++ {
-					char := lines[candidate.r][i]
-					if !unicode.IsDigit(rune(char)) {
-						break
-					}
-					end = i
-					seen[Point{r: candidate.r, c: i}] = 1
-				}
-				partNumber, err := strconv.Atoi(lines[candidate.r][start : end+1])
-				if err != nil {
-					return 0, fmt.Errorf("expected %s to be an integer: %w", lines[r][start:end], err)
-				}
-				partNumbers = append(partNumbers, partNumber)
-			}
+// Aggregation combines the values of a gear's numbers into its ratio
+type Aggregation int
 
-			if len(partNumbers) == 2 {
-				gearRatio := partNumbers[0] * partNumbers[1]
-				total += gearRatio
-			}
+const (
+	Product Aggregation = iota
+	Sum
+	Max
+)
+
+func ParseAggregation(name string) (Aggregation, error) {
+	switch name {
+	case "product":
+		return Product, nil
+	case "sum":
+		return Sum, nil
+	case "max":
+		return Max, nil
+	default:
+		return 0, fmt.Errorf("unknown aggregation %q, expected product, sum or max", name)
+	}
+}
+
+func (a Aggregation) apply(numbers []Number) int {
+	if len(numbers) == 0 {
+		return 0
+	}
+	result := numbers[0].Value
+	for _, n := range numbers[1:] {
+		switch a {
+		case Product:
+			result *= n.Value
+		case Sum:
+			result += n.Value
+		case Max:
+			result = max(result, n.Value)
 		}
 	}
+	return result
+}
 
-	return total, nil
+// GearRule decides which symbols are gears and what their ratio is
+type GearRule struct {
+	// Symbols are the runes that can be gears, or any symbol if empty
+	Symbols string
+	// Count reports whether a symbol touching n numbers is a gear
+	Count     func(n int) bool
+	Aggregate Aggregation
+	// Neighbours are the offsets from a symbol a number must cover to touch it,
+	// lib.Neighbours8 if nil
+	Neighbours []lib.Cell
+}
+
+// DefaultGearRule treats a `*` touching exactly two numbers as a gear, with their product as the ratio
+var DefaultGearRule = GearRule{Symbols: "*", Count: Exactly(2), Aggregate: Product}
+
+func Exactly(n int) func(int) bool {
+	return func(count int) bool { return count == n }
+}
+
+func Between(low, high int) func(int) bool {
+	return func(count int) bool { return count >= low && count <= high }
+}
+
+// ParseCount parses "N", ">=N" or "N-M" as a GearRule Count
+func ParseCount(spec string) (func(int) bool, error) {
+	if rest, ok := strings.CutPrefix(spec, ">="); ok {
+		n, err := strconv.Atoi(rest)
+		if err != nil {
+			return nil, fmt.Errorf("expected a number after >=, got %q", rest)
+		}
+		return Between(n, math.MaxInt), nil
+	}
+	if from, to, ok := strings.Cut(spec, "-"); ok {
+		low, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("expected a range N-M, got %q", spec)
+		}
+		high, err := strconv.Atoi(to)
+		if err != nil {
+			return nil, fmt.Errorf("expected a range N-M, got %q", spec)
+		}
+		if low > high {
+			return nil, fmt.Errorf("expected the start of range %q to be no more than its end", spec)
+		}
+		return Between(low, high), nil
+	}
+	n, err := strconv.Atoi(spec)
+	if err != nil {
+		return nil, fmt.Errorf("expected N, >=N or N-M, got %q", spec)
+	}
+	return Exactly(n), nil
+}
+
+type Gear struct {
+	Symbol  Symbol
+	Numbers []Number
+	Ratio   int
+}
+
+// Gears returns the symbols that are gears under rule, in reading order
+func (s *Schematic) Gears(rule GearRule) []Gear {
+	// the index is built with 8-connectivity, so only other neighbourhoods need a lookup
+	indexed := rule.Neighbours == nil || slices.Equal(rule.Neighbours, lib.Neighbours8)
+
+	gears := []Gear{}
+	for _, symbol := range s.Symbols {
+		if rule.Symbols != "" && !strings.ContainsRune(rule.Symbols, symbol.Rune) {
+			continue
+		}
+		ids := s.symbolNumbers[symbol.ID]
+		if !indexed {
+			ids = s.adjacent(symbol.Position, rule.Neighbours)
+		}
+		if rule.Count != nil && !rule.Count(len(ids)) {
+			continue
+		}
+		numbers := make([]Number, len(ids))
+		for i, id := range ids {
+			numbers[i] = s.Numbers[id]
+		}
+		gears = append(gears, Gear{Symbol: symbol, Numbers: numbers, Ratio: rule.Aggregate.apply(numbers)})
+	}
+	return gears
+}
+
+// SumRatios adds up the ratio of every gear under rule
+func (s *Schematic) SumRatios(rule GearRule) int {
+	total := 0
+	for _, gear := range s.Gears(rule) {
+		total += gear.Ratio
+	}
+	return total
+}
+
+func Part2(lines []string) (int, error) {
+	schematic, err := ParseSchematic(lines)
+	if err != nil {
+		return 0, err
+	}
+	return schematic.SumRatios(DefaultGearRule), nil
 }
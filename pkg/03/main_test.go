@@ -1,8 +1,11 @@
 package main
 
 import (
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/max-nicholson/advent-of-code-2023/lib"
 )
 
 func TestPart1(t *testing.T) {
@@ -42,3 +45,170 @@ func TestPart2(t *testing.T) {
 		t.Fatalf("expected 467835, got %d", total)
 	}
 }
+
+var example = strings.Split(`467..114..
+...*......
+..35..633.
+......#...
+617*......
+.....+.58.
+..592.....
+......755.
+...$.*....
+.664.598..`, "\n")
+
+func values(numbers []Number) []int {
+	values := make([]int, len(numbers))
+	for i, n := range numbers {
+		values[i] = n.Value
+	}
+	return values
+}
+
+func parseExample(t *testing.T) *Schematic {
+	schematic, err := ParseSchematic(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schematic
+}
+
+func TestParseSchematic(t *testing.T) {
+	schematic := parseExample(t)
+	if len(schematic.Numbers) != 10 {
+		t.Fatalf("expected 10 numbers, got %d", len(schematic.Numbers))
+	}
+	if len(schematic.Symbols) != 6 {
+		t.Fatalf("expected 6 symbols, got %d", len(schematic.Symbols))
+	}
+	want := Number{ID: 0, Value: 467, Row: 0, Start: 0, End: 3}
+	if schematic.Numbers[0] != want {
+		t.Fatalf("expected %+v, got %+v", want, schematic.Numbers[0])
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	result := values(parseExample(t).Orphans())
+	want := []int{114, 58}
+	if !slices.Equal(result, want) {
+		t.Fatalf("expected %v, got %v", want, result)
+	}
+}
+
+func TestPartsTouching(t *testing.T) {
+	schematic := parseExample(t)
+	var testCases = []struct {
+		symbol rune
+		want   []int
+	}{
+		{symbol: '*', want: []int{467, 35, 617, 755, 598}},
+		{symbol: '$', want: []int{664}},
+		{symbol: '%', want: []int{}},
+	}
+	for _, testCase := range testCases {
+		result := values(schematic.PartsTouching(testCase.symbol))
+		if !slices.Equal(result, testCase.want) {
+			t.Errorf("%c: expected %v, got %v", testCase.symbol, testCase.want, result)
+		}
+	}
+}
+
+func TestSymbolsWithParts(t *testing.T) {
+	schematic := parseExample(t)
+	var testCases = []struct {
+		parts int
+		want  []lib.Cell
+	}{
+		{parts: 0, want: []lib.Cell{}},
+		{parts: 1, want: []lib.Cell{{Row: 3, Column: 6}, {Row: 4, Column: 3}, {Row: 5, Column: 5}, {Row: 8, Column: 3}}},
+		{parts: 2, want: []lib.Cell{{Row: 1, Column: 3}, {Row: 8, Column: 5}}},
+	}
+	for _, testCase := range testCases {
+		result := []lib.Cell{}
+		for _, symbol := range schematic.SymbolsWithParts(testCase.parts) {
+			result = append(result, symbol.Position)
+		}
+		if !slices.Equal(result, testCase.want) {
+			t.Errorf("%d parts: expected %v, got %v", testCase.parts, testCase.want, result)
+		}
+	}
+}
+
+func TestNumbersOfSymbolsOf(t *testing.T) {
+	schematic := parseExample(t)
+
+	gear := schematic.SymbolsWithParts(2)[1]
+	result := values(schematic.NumbersOf(gear))
+	want := []int{755, 598}
+	if !slices.Equal(result, want) {
+		t.Fatalf("expected %v, got %v", want, result)
+	}
+
+	// the index works in both directions
+	for _, symbol := range schematic.Symbols {
+		for _, number := range schematic.NumbersOf(symbol) {
+			if !slices.Contains(schematic.SymbolsOf(number), symbol) {
+				t.Errorf("expected %+v to touch %+v, got %+v", number, symbol, schematic.SymbolsOf(number))
+			}
+		}
+	}
+}
+
+func TestGearRules(t *testing.T) {
+	schematic := parseExample(t)
+
+	var testCases = []struct {
+		rule GearRule
+		want int
+	}{
+		{rule: DefaultGearRule, want: 467835},
+		{rule: GearRule{Symbols: "*", Count: Exactly(2), Aggregate: Sum}, want: 467 + 35 + 755 + 598},
+		{rule: GearRule{Symbols: "*", Count: Exactly(2), Aggregate: Max}, want: 467 + 755},
+		{rule: GearRule{Symbols: "*", Count: Between(1, 2), Aggregate: Sum}, want: 467 + 35 + 617 + 755 + 598},
+		// any symbol
+		{rule: GearRule{Count: Exactly(1), Aggregate: Product}, want: 633 + 617 + 592 + 664},
+		// 467 and 755 only touch a * diagonally
+		{rule: GearRule{Symbols: "*", Count: Exactly(1), Aggregate: Product, Neighbours: lib.Neighbours4}, want: 35 + 617 + 598},
+	}
+	for i, testCase := range testCases {
+		result := schematic.SumRatios(testCase.rule)
+		if result != testCase.want {
+			t.Errorf("rule %d: expected %d, got %d", i, testCase.want, result)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	var testCases = []struct {
+		spec  string
+		match []int
+		miss  []int
+	}{
+		{spec: "2", match: []int{2}, miss: []int{1, 3}},
+		{spec: ">=2", match: []int{2, 3, 8}, miss: []int{0, 1}},
+		{spec: "1-3", match: []int{1, 2, 3}, miss: []int{0, 4}},
+	}
+	for _, testCase := range testCases {
+		count, err := ParseCount(testCase.spec)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		for _, n := range testCase.match {
+			if !count(n) {
+				t.Errorf("%s: expected %d to match, got no match", testCase.spec, n)
+			}
+		}
+		for _, n := range testCase.miss {
+			if count(n) {
+				t.Errorf("%s: expected %d not to match, got a match", testCase.spec, n)
+			}
+		}
+	}
+
+	for _, spec := range []string{"", "x", ">=x", "1-x", "3-1"} {
+		if _, err := ParseCount(spec); err == nil {
+			t.Errorf("%q: expected an error, got nil", spec)
+		}
+	}
+}
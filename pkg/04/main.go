@@ -1,53 +1,56 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/set"
 )
 
 type Scratchcard struct {
-	WinningNumbers map[int]int
+	ID             int
+	WinningNumbers set.Set[int]
 	Numbers        []int
 }
 
+var ErrDuplicateNumber = errors.New("duplicate number")
+
+// scratchcardRecord has no spaces in its pattern, so numbers can be any width with
+// any run of spaces or tabs around them
+type scratchcardRecord struct {
+	_       struct{} `aoc:"Card{ID}:{Winning}|{Numbers}"`
+	ID      int
+	Winning []int
+	Numbers []int
+}
+
 func NewScratchcard(card string) (*Scratchcard, error) {
-	cardInfo := strings.Split(card, ": ")
-	if len(cardInfo) != 2 {
-		return nil, fmt.Errorf("unable to parse %s", card)
-	}
-	data := strings.Split(cardInfo[1], " | ")
-	if len(data) != 2 {
-		return nil, fmt.Errorf("unable to parse %s", cardInfo)
-	}
-	numbers := make([]int, 8)
-	for i := 0; i < len(data[1]); i += 3 {
-		s := data[1][i : i+2]
-		v, err := strconv.Atoi(strings.TrimLeft(s, " "))
-		if err != nil {
-			return nil, fmt.Errorf("expected integer, got %s: %w", s, err)
-		}
-		numbers = append(numbers, v)
+	var record scratchcardRecord
+	if err := lib.Unmarshal(strings.TrimSpace(card), &record); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", card, err)
 	}
-	winningNumbers := make(map[int]int, 25)
-	for i := 0; i < len(data[0]); i += 3 {
-		s := data[0][i : i+2]
-		v, err := strconv.Atoi(strings.TrimLeft(s, " "))
-		if err != nil {
-			return nil, fmt.Errorf("expected integer, got %s: %w", s, err)
-		}
-		winningNumbers[v] = 1
+	winning := set.New(record.Winning...)
+	if winning.Len() != len(record.Winning) {
+		return nil, fmt.Errorf("card %d: %w in winning numbers %v", record.ID, ErrDuplicateNumber, record.Winning)
 	}
-	s := Scratchcard{WinningNumbers: winningNumbers, Numbers: numbers}
+	if set.New(record.Numbers...).Len() != len(record.Numbers) {
+		return nil, fmt.Errorf("card %d: %w in numbers %v", record.ID, ErrDuplicateNumber, record.Numbers)
+	}
+	s := Scratchcard{ID: record.ID, WinningNumbers: winning, Numbers: record.Numbers}
 	return &s, nil
 }
 
 func (s *Scratchcard) IsWinningNumber(number int) bool {
-	_, ok := s.WinningNumbers[number]
-	return ok
+	return s.WinningNumbers.Contains(number)
 }
 
 func (s *Scratchcard) Matches() int {
@@ -73,6 +76,9 @@ func (s *Scratchcard) Points() int {
 }
 
 func main() {
+	cascade := flag.String("cascade", "", "write the part 2 cascade of copies as text or json")
+	flag.Parse()
+
 	lines, err := lib.ReadLines("pkg/04/input.txt")
 	if err != nil {
 		log.Fatal(err)
@@ -89,58 +95,174 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("part2: %v\n", part2)
+
+	if *cascade != "" {
+		cards, err := ParseScratchcards(lines)
+		if err != nil {
+			log.Fatal(err)
+		}
+		report := NewCascade(cards)
+		if err := report.Write(os.Stdout, *cascade); err != nil {
+			log.Fatal(err)
+		}
+		if err := report.Check(CountCards(cards)); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
 func Part1(lines []string) (int, error) {
-	total := 0
+	cards, err := ParseScratchcards(lines)
+	if err != nil {
+		return 0, err
+	}
 
-	for i, line := range lines {
-		card, err := NewScratchcard(line)
-		if err != nil {
-			return 0, fmt.Errorf("unable to parse scratchcard %d: %w", i+1, err)
-		}
+	total := 0
+	for _, card := range cards {
 		total += card.Points()
 	}
 
 	return total, nil
 }
 
-func Part2(lines []string) (int, error) {
-	winsByCard := make(map[int]int, len(lines))
-
+// ParseScratchcards parses a card per line, in table order
+func ParseScratchcards(lines []string) ([]*Scratchcard, error) {
+	cards := make([]*Scratchcard, len(lines))
 	for i, line := range lines {
 		card, err := NewScratchcard(line)
 		if err != nil {
-			return 0, fmt.Errorf("unable to parse scratchcard %d: %w", i+1, err)
+			return nil, fmt.Errorf("unable to parse scratchcard %d: %w", i+1, err)
 		}
-		winsByCard[i+1] = card.Matches()
+		cards[i] = card
 	}
+	return cards, nil
+}
 
-	numberOfCards := make(map[int]int, len(lines))
-
-	for i := 0; i < len(lines); i++ {
-		cardId := i + 1
-		wins, ok := winsByCard[cardId]
-		if !ok {
-			return 0, fmt.Errorf("card %d not found in wins map", cardId)
+// CountCards is the number of cards held once every copy has been won. A card with
+// n matches wins a copy of each of the next n cards in the table, stopping at the
+// end of the table
+func CountCards(cards []*Scratchcard) int {
+	copies := make([]int, len(cards))
+	total := 0
+	for i, card := range cards {
+		// the original plus every copy won by earlier cards
+		held := copies[i] + 1
+		total += held
+		for j := i + 1; j <= i+card.Matches() && j < len(cards); j++ {
+			copies[j] += held
 		}
-		copies := numberOfCards[cardId]
-		var multiplier = 1
-		if copies != 0 {
-			multiplier += copies
+	}
+	return total
+}
+
+// CardReport is how a card fared in the cascade of copies
+type CardReport struct {
+	ID      int `json:"id"`
+	Matches int `json:"matches"`
+	// Wins are the IDs of the cards each copy of this card wins a copy of
+	Wins []int `json:"wins"`
+	// Overflow is how many matches run past the end of the table, winning nothing
+	Overflow int `json:"overflow,omitempty"`
+	// CopiesWon is the number of copies of this card won by earlier cards
+	CopiesWon int `json:"copiesWon"`
+	// Held is the original card plus every copy won
+	Held int `json:"held"`
+	// CopiesGiven is the number of copies of later cards that every held copy of
+	// this card wins between them
+	CopiesGiven int `json:"copiesGiven"`
+	// SpawnedBy are the IDs of the cards that win copies of this one
+	SpawnedBy []int `json:"spawnedBy"`
+}
+
+// Cascade follows every copy won from a table of scratchcards
+type Cascade struct {
+	Cards []CardReport `json:"cards"`
+	Total int          `json:"total"`
+}
+
+// NewCascade works out who wins what, then how many of each card is held from the
+// cards that spawn it. That is the reverse of CountCards, so the two check each other
+func NewCascade(cards []*Scratchcard) *Cascade {
+	reports := make([]CardReport, len(cards))
+	for i, card := range cards {
+		reports[i] = CardReport{ID: card.ID, Matches: card.Matches(), Wins: []int{}, SpawnedBy: []int{}}
+	}
+	spawners := make([][]int, len(cards))
+	for i := range reports {
+		for j := i + 1; j <= i+reports[i].Matches; j++ {
+			if j >= len(cards) {
+				reports[i].Overflow++
+				continue
+			}
+			reports[i].Wins = append(reports[i].Wins, reports[j].ID)
+			reports[j].SpawnedBy = append(reports[j].SpawnedBy, reports[i].ID)
+			spawners[j] = append(spawners[j], i)
 		}
-		// original
-		numberOfCards[cardId] += 1
-		// copies
-		for win := 1; win <= wins; win++ {
-			numberOfCards[cardId+win] += multiplier
+	}
+
+	c := &Cascade{Cards: reports}
+	for i := range reports {
+		report := &reports[i]
+		report.Held = 1
+		for _, j := range spawners[i] {
+			report.Held += reports[j].Held
 		}
+		report.CopiesWon = report.Held - 1
+		report.CopiesGiven = report.Held * len(report.Wins)
+		c.Total += report.Held
 	}
+	return c
+}
 
-	total := 0
-	for _, t := range numberOfCards {
-		total += t
+var ErrCascadeMismatch = errors.New("cascade total doesn't match")
+
+// Check returns ErrCascadeMismatch unless the cascade holds total cards, e.g from CountCards
+func (c *Cascade) Check(total int) error {
+	if c.Total != total {
+		return fmt.Errorf("%w: cascade holds %d cards, expected %d", ErrCascadeMismatch, c.Total, total)
 	}
+	return nil
+}
 
-	return total, nil
+func formatIDs(ids []int) string {
+	if len(ids) == 0 {
+		return "-"
+	}
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return strings.Join(s, ",")
+}
+
+// Write writes the cascade to w as a "text" table, or as "json"
+func (c *Cascade) Write(w io.Writer, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(c)
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "card\tmatches\twins\twon\theld\tgiven\tspawned by")
+		for _, card := range c.Cards {
+			wins := formatIDs(card.Wins)
+			if card.Overflow > 0 {
+				wins += fmt.Sprintf(" (+%d past end)", card.Overflow)
+			}
+			fmt.Fprintf(tw, "%d\t%d\t%s\t%d\t%d\t%d\t%s\n", card.ID, card.Matches, wins, card.CopiesWon, card.Held, card.CopiesGiven, formatIDs(card.SpawnedBy))
+		}
+		fmt.Fprintf(tw, "total\t\t\t\t%d\n", c.Total)
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q, expected text or json", format)
+	}
+}
+
+func Part2(lines []string) (int, error) {
+	cards, err := ParseScratchcards(lines)
+	if err != nil {
+		return 0, err
+	}
+	return CountCards(cards), nil
 }
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"errors"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/max-nicholson/advent-of-code-2023/lib/set"
 )
 
 func TestPart1(t *testing.T) {
@@ -34,3 +38,137 @@ Card 6: 31 18 13 56 72 | 74 77 10 23 35 67 36 11`, "\n"))
 		t.Fatalf("expected 30, got %d", total)
 	}
 }
+
+func TestNewScratchcard(t *testing.T) {
+	var testCases = []struct {
+		card    string
+		id      int
+		winning []int
+		numbers []int
+	}{
+		{card: "Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53", id: 1, winning: []int{41, 48, 83, 86, 17}, numbers: []int{83, 86, 6, 31, 17, 9, 48, 53}},
+		{card: "Card   12:  1 21 | 69  1", id: 12, winning: []int{1, 21}, numbers: []int{69, 1}},
+		{card: "Card 3: 100 2000 | 30000 100", id: 3, winning: []int{100, 2000}, numbers: []int{30000, 100}},
+		{card: "Card 4:1 2|3 4", id: 4, winning: []int{1, 2}, numbers: []int{3, 4}},
+		{card: "  Card\t5 :\t1\t2  |\t3  ", id: 5, winning: []int{1, 2}, numbers: []int{3}},
+	}
+	for _, testCase := range testCases {
+		card, err := NewScratchcard(testCase.card)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if card.ID != testCase.id {
+			t.Errorf("%q: expected ID %d, got %d", testCase.card, testCase.id, card.ID)
+		}
+		if want := set.New(testCase.winning...); !card.WinningNumbers.Equal(want) {
+			t.Errorf("%q: expected winning numbers %v, got %v", testCase.card, set.Sorted(want), set.Sorted(card.WinningNumbers))
+		}
+		if !slices.Equal(card.Numbers, testCase.numbers) {
+			t.Errorf("%q: expected numbers %v, got %v", testCase.card, testCase.numbers, card.Numbers)
+		}
+	}
+}
+
+func TestNewScratchcardErrors(t *testing.T) {
+	for _, card := range []string{
+		"Card 1: 41 41 | 1 2",
+		"Card 1: 41 42 | 2 2",
+	} {
+		if _, err := NewScratchcard(card); !errors.Is(err, ErrDuplicateNumber) {
+			t.Fatalf("%q: expected ErrDuplicateNumber, got %v", card, err)
+		}
+	}
+	for _, card := range []string{
+		"Card x: 1 | 2",
+		"Card 1 1 | 2",
+		"Card 1: 1 2",
+		"Game 1: 1 | 2",
+	} {
+		if _, err := NewScratchcard(card); err == nil {
+			t.Fatalf("%q: expected an error", card)
+		}
+	}
+}
+
+var example = strings.Split(`Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53
+Card 2: 13 32 20 16 61 | 61 30 68 82 17 32 24 19
+Card 3:  1 21 53 59 44 | 69 82 63 72 16 21 14  1
+Card 4: 41 92 73 84 69 | 59 84 76 51 58  5 54 83
+Card 5: 87 83 26 28 32 | 88 30 70 12 93 22 82 36
+Card 6: 31 18 13 56 72 | 74 77 10 23 35 67 36 11`, "\n")
+
+func TestCascade(t *testing.T) {
+	cards, err := ParseScratchcards(example)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cascade := NewCascade(cards)
+	if err := cascade.Check(CountCards(cards)); err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	if err := cascade.Write(&sb, "text"); err != nil {
+		t.Fatal(err)
+	}
+	want := `card   matches  wins     won  held  given  spawned by
+1      4        2,3,4,5  0    1     4      -
+2      2        3,4      1    2     4      1
+3      2        4,5      3    4     8      1,2
+4      1        5        7    8     8      1,2,3
+5      0        -        13   14    0      1,3,4
+6      0        -        0    1     0      -
+total                         30
+`
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+
+	sb.Reset()
+	if err := (&Cascade{Cards: cascade.Cards[5:], Total: 1}).Write(&sb, "json"); err != nil {
+		t.Fatal(err)
+	}
+	want = `{
+  "cards": [
+    {
+      "id": 6,
+      "matches": 0,
+      "wins": [],
+      "copiesWon": 0,
+      "held": 1,
+      "copiesGiven": 0,
+      "spawnedBy": []
+    }
+  ],
+  "total": 1
+}
+`
+	if sb.String() != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, sb.String())
+	}
+}
+
+func TestCascadeOverflow(t *testing.T) {
+	cards, err := ParseScratchcards([]string{
+		"Card 1: 1 2 | 1 3",
+		"Card 2: 1 2 3 | 1 2 3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// card 2's wins all run past the end of the table, so win nothing
+	if total := CountCards(cards); total != 3 {
+		t.Fatalf("expected 3, got %d", total)
+	}
+	cascade := NewCascade(cards)
+	if err := cascade.Check(3); err != nil {
+		t.Fatal(err)
+	}
+	if card := cascade.Cards[1]; card.Overflow != 3 || len(card.Wins) != 0 || card.Held != 2 {
+		t.Fatalf("got %+v", card)
+	}
+	if err := cascade.Check(4); !errors.Is(err, ErrCascadeMismatch) {
+		t.Fatalf("expected ErrCascadeMismatch, got %v", err)
+	}
+}
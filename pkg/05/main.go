@@ -4,10 +4,9 @@ import (
 	"fmt"
 	"log"
 	"math"
-	"strconv"
-	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/parse"
 )
 
 type CategoryRange struct {
@@ -35,6 +34,17 @@ func (c *Category) Map(number int) int {
 	return number
 }
 
+func parseRange(line string) ([]int, error) {
+	ints, err := parse.Ints(line)
+	if err != nil {
+		return nil, err
+	}
+	if len(ints) != 3 {
+		return nil, fmt.Errorf("expected destination start, source start and length, got %s", line)
+	}
+	return ints, nil
+}
+
 func main() {
 	lines, err := lib.ReadLines("pkg/05/input.txt")
 	if err != nil {
@@ -55,14 +65,9 @@ func main() {
 }
 
 func Part1(lines []string) (int, error) {
-	seeds := strings.Split(lines[0][7:], " ")
-	seedIds := make([]int, len(seeds))
-	for i, seed := range seeds {
-		v, err := strconv.Atoi(seed)
-		if err != nil {
-			return 0, err
-		}
-		seedIds[i] = v
+	var seedIds []int
+	if _, err := parse.Match(lines[0], "seeds:", &seedIds); err != nil {
+		return 0, fmt.Errorf("unable to parse seeds: %w", err)
 	}
 
 	categories := []Category{}
@@ -76,10 +81,9 @@ func Part1(lines []string) (int, error) {
 			continue
 		}
 
-		values := strings.Split(line, " ")
-		ints := make([]int, 3)
-		for j, value := range values {
-			ints[j], _ = strconv.Atoi(value)
+		ints, err := parseRange(line)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+1, err)
 		}
 
 		category.Ranges = append(category.Ranges, CategoryRange{DestinationStart: ints[0], SourceStart: ints[1], Length: ints[2]})
@@ -115,15 +119,18 @@ type Map struct {
 }
 
 func Part2(lines []string) (int, error) {
-	seeds := strings.Split(lines[0][7:], " ")
+	var seeds []int
+	if _, err := parse.Match(lines[0], "seeds:", &seeds); err != nil {
+		return 0, fmt.Errorf("unable to parse seeds: %w", err)
+	}
 	if len(seeds)%2 != 0 {
 		return 0, fmt.Errorf("expected pairs of seeds")
 	}
 
 	ranges := []Range{}
 	for i := 0; i < len(seeds); i += 2 {
-		start, _ := strconv.Atoi(seeds[i])
-		length, _ := strconv.Atoi(seeds[i+1])
+		start := seeds[i]
+		length := seeds[i+1]
 
 		ranges = append(ranges, Range{Start: start, End: start + length - 1})
 	}
@@ -139,10 +146,9 @@ func Part2(lines []string) (int, error) {
 			continue
 		}
 
-		values := strings.Split(line, " ")
-		ints := make([]int, 3)
-		for j, value := range values {
-			ints[j], _ = strconv.Atoi(value)
+		ints, err := parseRange(line)
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", i+1, err)
 		}
 
 		m.Ranges = append(m.Ranges, MapRange{
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/parse"
 )
 
 func main() {
@@ -33,57 +34,31 @@ type Race struct {
 	Time     int
 }
 
-func (r *Race) Length() int {
-	time := r.Time
-	distance := r.Distance
-	start := 0
-	for press := 1; press < time-1; press++ {
-		speed := press
-		travelTime := time - press
-
-		d := travelTime * speed
-		if d > distance {
-			start = press
-			break
-		}
-	}
-	end := 0
-	for press := time - 1; press > 0; press-- {
-		speed := press
-		travelTime := time - press
-
-		d := travelTime * speed
-		if d > distance {
-			end = press
-			break
-		}
+func (r *Race) Length() (int, error) {
+	// Holding the button for `press` travels press * (time - press), which must beat distance:
+	// press^2 - time*press + distance < 0
+	presses, err := lib.QuadraticInterval(1, -r.Time, r.Distance, true)
+	if err != nil {
+		return 0, fmt.Errorf("unable to solve race %v: %w", *r, err)
 	}
-
-	return end - start + 1
+	presses.Start = max(presses.Start, 0)
+	presses.End = min(presses.End, r.Time)
+	return presses.Len(), nil
 }
 
 func Part1(lines []string) (int, error) {
-	var ParseLine = func(line string) ([]int, error) {
-		values := []int{}
-
-		for _, s := range strings.Split(line[9:], " ") {
-			s = strings.Trim(s, " ")
-			if s == "" {
-				continue
-			}
-			v, err := strconv.Atoi(s)
-			if err != nil {
-				return nil, fmt.Errorf("unable to convert %s to int", s)
-			}
-			values = append(values, v)
+	var ParseLine = func(line string, label string) ([]int, error) {
+		var values []int
+		if _, err := parse.Match(line, label, &values); err != nil {
+			return nil, err
 		}
 		return values, nil
 	}
-	times, err := ParseLine(lines[0])
+	times, err := ParseLine(lines[0], "Time:")
 	if err != nil {
 		return 0, fmt.Errorf("unable to parse times: %w", err)
 	}
-	distances, err := ParseLine(lines[1])
+	distances, err := ParseLine(lines[1], "Distance:")
 	if err != nil {
 		return 0, fmt.Errorf("unable to parse distances: %w", err)
 	}
@@ -95,7 +70,10 @@ func Part1(lines []string) (int, error) {
 	ways := 0
 	for r := 0; r < races; r++ {
 		race := Race{Time: times[r], Distance: distances[r]}
-		length := race.Length()
+		length, err := race.Length()
+		if err != nil {
+			return 0, err
+		}
 		if r == 0 {
 			ways = length
 		} else {
@@ -107,8 +85,11 @@ func Part1(lines []string) (int, error) {
 }
 
 func Part2(lines []string) (int, error) {
-	var ParseLine = func(line string) (int, error) {
-		data := strings.Split(line, ": ")[1]
+	var ParseLine = func(line string, label string) (int, error) {
+		var data string
+		if _, err := parse.Match(line, label, &data); err != nil {
+			return 0, err
+		}
 		s := strings.ReplaceAll(data, " ", "")
 		v, err := strconv.Atoi(s)
 		if err != nil {
@@ -117,18 +98,16 @@ func Part2(lines []string) (int, error) {
 		return v, nil
 	}
 
-	time, err := ParseLine(lines[0])
+	time, err := ParseLine(lines[0], "Time:")
 	if err != nil {
 		return 0, fmt.Errorf("unable to parse times: %w", err)
 	}
-	distance, err := ParseLine(lines[1])
+	distance, err := ParseLine(lines[1], "Distance:")
 	if err != nil {
 		return 0, fmt.Errorf("unable to parse distances: %w", err)
 	}
 
 	race := Race{Time: time, Distance: distance}
 
-	length := race.Length()
-
-	return length, nil
+	return race.Length()
 }
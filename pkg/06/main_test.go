@@ -26,3 +26,24 @@ Distance:  9  40  200`, "\n"))
 		t.Fatalf("expected 71503, got %d", total)
 	}
 }
+
+func TestRaceLength(t *testing.T) {
+	var testCases = []struct {
+		race Race
+		want int
+	}{
+		{race: Race{Time: 7, Distance: 9}, want: 4},
+		{race: Race{Time: 15, Distance: 40}, want: 8},
+		{race: Race{Time: 30, Distance: 200}, want: 9},
+		{race: Race{Time: 4, Distance: 4}, want: 0},
+	}
+	for _, testCase := range testCases {
+		got, err := testCase.race.Length()
+		if err != nil {
+			t.Error(err)
+		}
+		if got != testCase.want {
+			t.Errorf("%v: expected %d, got %d", testCase.race, testCase.want, got)
+		}
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"log"
 	"sort"
-	"strconv"
-	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
 )
@@ -106,26 +104,26 @@ type Round struct {
 	Bid  int
 }
 
-func ParseRound(round string, options *GameOptions) (*Round, error) {
-	parts := strings.Split(round, " ")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("expected a hand and bid separated by a space")
-	}
-	cards := strings.Split(parts[0], "")
+type roundRecord struct {
+	_     struct{} `aoc:"{Cards} {Bid}"`
+	Cards []string `sep:""`
+	Bid   int
+}
 
-	hand, err := NewHand(cards, options)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse hand %v: %w", cards, err)
+func ParseRound(round string, options *GameOptions) (*Round, error) {
+	var record roundRecord
+	if err := lib.Unmarshal(round, &record); err != nil {
+		return nil, fmt.Errorf("expected a hand and bid separated by a space: %w", err)
 	}
 
-	bid, err := strconv.Atoi(parts[1])
+	hand, err := NewHand(record.Cards, options)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse bid %s: %w", parts[1], err)
+		return nil, fmt.Errorf("failed to parse hand %v: %w", record.Cards, err)
 	}
 
 	return &Round{
 		Hand: hand,
-		Bid:  bid,
+		Bid:  record.Bid,
 	}, nil
 }
 
@@ -6,6 +6,8 @@ import (
 	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/graph"
+	"github.com/max-nicholson/advent-of-code-2023/lib/numtheory"
 )
 
 func main() {
@@ -27,7 +29,9 @@ func main() {
 	fmt.Printf("part2: %d\n", part2)
 }
 
-type Node struct {
+type nodeRecord struct {
+	_     struct{} `aoc:"{Name} = ({Left}, {Right})"`
+	Name  string
 	Left  string
 	Right string
 }
@@ -36,69 +40,55 @@ func ParseInstructions(line string) []string {
 	return strings.Split(line, "")
 }
 
-func ParseNodes(lines []string) (map[string]Node, error) {
-	var nodes = make(map[string]Node, len(lines))
+// ParseNetwork builds a directed graph with an "L" and an "R" edge leaving every node
+func ParseNetwork(lines []string) (*graph.Graph[string, string], error) {
+	network := graph.NewDirected[string, string]()
 	for i, line := range lines {
-		parts := strings.Split(line, " = ")
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("expected line %d to split by ' = ' into 2", i)
+		var node nodeRecord
+		if err := lib.Unmarshal(line, &node); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i, err)
 		}
-		directions := strings.Split(parts[1], ", ")
-		nodes[parts[0]] = Node{Left: directions[0][1:4], Right: directions[1][:3]}
+		network.AddEdge(node.Name, node.Left, "L")
+		network.AddEdge(node.Name, node.Right, "R")
 	}
-	return nodes, nil
+	return network, nil
 }
 
-func Part1(lines []string) (int, error) {
+// Walk follows instructions from start until done reports true, returning the number of steps taken
+func Walk(network *graph.Graph[string, string], instructions []string, start string, done func(node string) bool) (int, error) {
+	node := start
 	step := 0
-
-	instructions := ParseInstructions(lines[0])
-	nodes, err := ParseNodes(lines[2:])
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse nodes: %w", err)
-	}
-	node := "AAA"
-	for {
-		if node == "ZZZ" {
-			return step, nil
-		}
+	for !done(node) {
 		instruction := instructions[step%len(instructions)]
-		if instruction == "L" {
-			node = nodes[node].Left
-		} else {
-			node = nodes[node].Right
+		next, ok := network.Next(node, instruction)
+		if !ok {
+			return 0, fmt.Errorf("no %s from node %s", instruction, node)
 		}
+		node = next
 		step += 1
 	}
+	return step, nil
 }
 
-func GCD(a, b int) int {
-	for b != 0 {
-		t := b
-		b = a % b
-		a = t
-	}
-	return a
-}
-
-func LCM(a, b int, integers ...int) int {
-	result := a * b / GCD(a, b)
-
-	for i := 0; i < len(integers); i++ {
-		result = LCM(result, integers[i])
+func Part1(lines []string) (int, error) {
+	instructions := ParseInstructions(lines[0])
+	network, err := ParseNetwork(lines[2:])
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse nodes: %w", err)
 	}
-
-	return result
+	return Walk(network, instructions, "AAA", func(node string) bool {
+		return node == "ZZZ"
+	})
 }
 
 func Part2(lines []string) (int, error) {
 	instructions := ParseInstructions(lines[0])
-	allNodes, err := ParseNodes(lines[2:])
+	network, err := ParseNetwork(lines[2:])
 	if err != nil {
 		return 0, fmt.Errorf("failed to parse nodes: %w", err)
 	}
 	nodes := []string{}
-	for node := range allNodes {
+	for _, node := range network.Nodes() {
 		if strings.HasSuffix(node, "A") {
 			nodes = append(nodes, node)
 		}
@@ -106,28 +96,22 @@ func Part2(lines []string) (int, error) {
 
 	ends := make([]int, len(nodes))
 	for i, start := range nodes {
-		node := start
-		step := 0
-
-		for {
-			if strings.HasSuffix(node, "Z") {
-				// Turns out the answer only needs the first "end" node, since subsequent moves
-				// are a cycle
-				// This would need to be more complicated if we expected a slice of "end"s
-				break
-			}
-
-			instruction := instructions[step%len(instructions)]
-			if instruction == "L" {
-				node = allNodes[node].Left
-			} else {
-				node = allNodes[node].Right
-			}
-			step += 1
+		// Turns out the answer only needs the first "end" node, since subsequent moves
+		// are a cycle
+		// This would need to be more complicated if we expected a slice of "end"s
+		step, err := Walk(network, instructions, start, func(node string) bool {
+			return strings.HasSuffix(node, "Z")
+		})
+		if err != nil {
+			return 0, err
 		}
 
 		ends[i] = step
 	}
 
-	return LCM(ends[0], ends[1], ends[2:]...), nil
+	steps, err := numtheory.CheckedLCM(ends...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to combine cycle lengths: %w", err)
+	}
+	return steps, nil
 }
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
+
+	"github.com/max-nicholson/advent-of-code-2023/lib/numtheory"
 )
 
 func TestPart1(t *testing.T) {
@@ -56,3 +60,35 @@ XXX = (XXX, XXX)`, "\n"))
 		t.Fatalf("expected 6, got %d", result)
 	}
 }
+
+func TestPart2Overflow(t *testing.T) {
+	// each ghost's first Z is a prime number of steps away, so they only line up after
+	// the product of the primes up to 53 steps, which doesn't fit in an int
+	primes := []int{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 43, 47, 53}
+	// node names are three characters: the ghost, the step along its path, and the kind
+	const steps = "0123456789abcdefghijklmnopqrstuvwxyzBCDEFGHIJKLMNOPQR"
+	name := func(ghost int, step int, kind byte) string {
+		return string([]byte{'a' + byte(ghost), steps[step], kind})
+	}
+
+	lines := []string{"L", ""}
+	for ghost, p := range primes {
+		for step := 0; step < p; step++ {
+			from, to := name(ghost, step, 'N'), name(ghost, 0, 'Z')
+			if step == 0 {
+				from = name(ghost, 0, 'A')
+			}
+			if step < p-1 {
+				to = name(ghost, step+1, 'N')
+			}
+			lines = append(lines, fmt.Sprintf("%s = (%s, %s)", from, to, to))
+		}
+		end := name(ghost, 0, 'Z')
+		lines = append(lines, fmt.Sprintf("%s = (%s, %s)", end, end, end))
+	}
+
+	_, err := Part2(lines)
+	if !errors.Is(err, numtheory.ErrOverflow) {
+		t.Fatalf("expected ErrOverflow, got %v", err)
+	}
+}
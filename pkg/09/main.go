@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/poly"
 )
 
 func main() {
@@ -41,34 +42,15 @@ func ParseLine(line string) ([]int, error) {
 	return values, nil
 }
 
-func allSameValue(values []int) bool {
-	target := values[0]
-
-	for i := 1; i < len(values); i++ {
-		if values[i] != target {
-			return false
-		}
+func Extrapolate(values []int, x int) (int, error) {
+	v := poly.Extrapolate(poly.Ints(values), x)
+	if !v.IsInt() {
+		return 0, fmt.Errorf("expected an integer at %d, got %s", x, v.RatString())
 	}
-	return true
-}
-
-func buildSequences(values []int) [][]int {
-	sequences := [][]int{values}
-	for {
-		values = sequences[len(sequences)-1]
-		newValues := make([]int, len(values)-1)
-		for i := 0; i < len(values)-1; i++ {
-			newValues[i] = values[i+1] - values[i]
-		}
-
-		sequences = append(sequences, newValues)
-
-		if allSameValue(newValues) {
-			break
-		}
+	if !v.Num().IsInt64() {
+		return 0, fmt.Errorf("value %s at %d does not fit in an int", v.RatString(), x)
 	}
-
-	return sequences
+	return int(v.Num().Int64()), nil
 }
 
 func Part1(lines []string) (int, error) {
@@ -79,11 +61,11 @@ func Part1(lines []string) (int, error) {
 		if err != nil {
 			return 0, err
 		}
-		sequences := buildSequences(values)
-
-		for _, seq := range sequences {
-			total += seq[len(seq)-1]
+		next, err := Extrapolate(values, len(values))
+		if err != nil {
+			return 0, fmt.Errorf("unable to extrapolate line %s: %w", line, err)
 		}
+		total += next
 	}
 	return total, nil
 }
@@ -96,19 +78,11 @@ func Part2(lines []string) (int, error) {
 		if err != nil {
 			return 0, err
 		}
-		sequences := buildSequences(values)
-
-		previousValue := sequences[0][0]
-		for i := 1; i < len(sequences); i++ {
-			seq := sequences[i]
-			delta := seq[0]
-			if i%2 == 0 {
-				previousValue += delta
-			} else {
-				previousValue -= delta
-			}
+		previous, err := Extrapolate(values, -1)
+		if err != nil {
+			return 0, fmt.Errorf("unable to extrapolate line %s: %w", line, err)
 		}
-		total += previousValue
+		total += previous
 	}
 	return total, nil
 }
@@ -28,3 +28,21 @@ func TestPart2(t *testing.T) {
 		t.Fatalf("expected 2, got %d", total)
 	}
 }
+
+func TestExtrapolateSingleValue(t *testing.T) {
+	total, err := Part1([]string{"5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 5 {
+		t.Fatalf("expected 5, got %d", total)
+	}
+}
+
+func TestExtrapolateOverflow(t *testing.T) {
+	// the next value in the sequence is 2^63
+	_, err := Extrapolate([]int{0, 1 << 62}, 2)
+	if err == nil {
+		t.Fatalf("expected an error, got nil")
+	}
+}
@@ -1,18 +1,120 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"image/color"
+	"io"
 	"log"
+	"os"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/bitgrid"
+	"github.com/max-nicholson/advent-of-code-2023/lib/geom"
 )
 
+var (
+	loopStyle    = lib.Style{Background: color.RGBA{R: 0x20, G: 0xc0, B: 0x60, A: 0xff}}
+	imageOptions = lib.ImageOptions{
+		CellSize:   3,
+		Palette:    map[rune]color.Color{'S': color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff}},
+		Background: color.RGBA{R: 0x10, G: 0x10, B: 0x18, A: 0xff},
+	}
+)
+
+// WriteLoopGIF writes an animation of the loop being traced from S to path, in
+// roughly `frames` frames, or 1 if frames is below 1
+func WriteLoopGIF(grid []string, path string, frames int) error {
+	frames = max(frames, 1)
+	loop, err := Loop(grid)
+	if err != nil {
+		return err
+	}
+	cells := loopCells(loop)
+
+	animation := lib.NewAnimation(imageOptions, 4)
+	step := max(len(cells)/frames, 1)
+	for end := step; end < len(cells)+step; end += step {
+		animation.AddFrame(lib.Lines(grid), lib.HighlightCells(cells[:min(end, len(cells))], loopStyle))
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file at %s: %w", path, err)
+	}
+	if err := animation.WriteGIF(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write GIF to %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func loopCells(loop []Point) []lib.Cell {
+	cells := make([]lib.Cell, len(loop))
+	for i, point := range loop {
+		cells[i] = lib.Cell{Row: point.Row, Column: point.Column}
+	}
+	return cells
+}
+
+// WriteLoopSVG writes the loop to w, closed back at S, over a heatmap of how far
+// each tile of the loop is from S. Tiles off the loop are 0
+func WriteLoopSVG(w io.Writer, grid []string) error {
+	loop, err := Loop(grid)
+	if err != nil {
+		return err
+	}
+
+	distances := make([][]int, len(grid))
+	for row := range grid {
+		distances[row] = make([]int, len(grid[row]))
+	}
+	for i, point := range loop {
+		// the loop can be walked either way round from S
+		distances[point.Row][point.Column] = min(i, len(loop)-i)
+	}
+
+	cells := loopCells(loop)
+	return lib.WriteSVG(w, distances, append(cells, cells[0]), lib.SVGOptions{
+		CellSize:   8,
+		Low:        color.RGBA{R: 0x10, G: 0x10, B: 0x18, A: 0xff},
+		High:       color.RGBA{R: 0x20, G: 0xc0, B: 0x60, A: 0xff},
+		Stroke:     color.RGBA{R: 0xff, G: 0x40, B: 0x40, A: 0xff},
+		ArrowEvery: max(len(loop)/50, 1),
+	})
+}
+
 func main() {
+	gifPath := flag.String("gif", "", "write an animated GIF of the loop being traced to this path")
+	frames := flag.Int("frames", 100, "the number of frames with --gif")
+	svgPath := flag.String("svg", "", "write the loop over a heatmap of distance from S as an SVG to this path")
+	check := flag.Bool("check", false, "cross-check part 2 by flood filling around the loop")
+	flag.Parse()
+
 	grid, err := lib.ReadLines("pkg/10/input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if *gifPath != "" {
+		if err := WriteLoopGIF(grid, *gifPath, *frames); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *svgPath != "" {
+		f, err := os.Create(*svgPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := WriteLoopSVG(f, grid); err != nil {
+			f.Close()
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	part1, err := Part1(grid)
 	if err != nil {
 		log.Fatal(err)
@@ -24,6 +126,17 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("part2: %d\n", part2)
+
+	if *check {
+		loop, err := Loop(grid)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if filled := EnclosedTilesByFloodFill(grid, loop); filled != part2 {
+			log.Fatalf("flood fill found %d enclosed tiles, expected %d", filled, part2)
+		}
+		fmt.Println("check: flood fill agrees")
+	}
 }
 
 type Point struct {
@@ -116,10 +229,11 @@ var DELTA = map[byte]*Point{
 	'E': {Column: 1},
 }
 
-func Part1(grid []string) (int, error) {
+// Loop returns every point of the loop through S, in the order they are walked, starting at S
+func Loop(grid []string) ([]Point, error) {
 	start, ok := FindStart(grid)
 	if !ok {
-		return 0, fmt.Errorf("start not found")
+		return nil, fmt.Errorf("start not found")
 	}
 
 	column := start.Column
@@ -127,20 +241,20 @@ func Part1(grid []string) (int, error) {
 	direction := FindStartDirection(grid, start)
 
 	if direction == 0 {
-		return 0, fmt.Errorf("unable to find a direction from S")
+		return nil, fmt.Errorf("unable to find a direction from S")
 	}
 
 	delta := DELTA[direction]
 	row += delta.Row
 	column += delta.Column
-
-	distance := 1
+	loop := []Point{*start}
 
 	for !(column == start.Column && row == start.Row) {
+		loop = append(loop, Point{Row: row, Column: column})
 		nextDirection, err := NextDirection(grid[row][column], direction)
 		//fmt.Printf("[%d][%d] %s %s\n", row, column, string(grid[row][column]), string(direction))
 		if nextDirection == 0 {
-			return 0, fmt.Errorf(
+			return nil, fmt.Errorf(
 				"unable to find a next direction from %s at [%d][%d] going %s: %w",
 				string(grid[row][column]),
 				row,
@@ -153,9 +267,18 @@ func Part1(grid []string) (int, error) {
 		delta := DELTA[direction]
 		row += delta.Row
 		column += delta.Column
-		distance += 1
 	}
 
+	return loop, nil
+}
+
+func Part1(grid []string) (int, error) {
+	loop, err := Loop(grid)
+	if err != nil {
+		return 0, err
+	}
+
+	distance := len(loop)
 	if distance%2 == 0 {
 		return distance / 2, nil
 	} else {
@@ -163,86 +286,56 @@ func Part1(grid []string) (int, error) {
 	}
 }
 
-func Part2(grid []string) (int, error) {
-	start, ok := FindStart(grid)
-	if !ok {
-		return 0, fmt.Errorf("start not found")
+// EnclosedTiles counts the tiles inside the loop with Pick's theorem, since the loop's
+// tiles are the vertices (and boundary points) of a lattice polygon
+func EnclosedTiles(loop []Point) int {
+	vertices := make([]geom.Point, len(loop))
+	for i, point := range loop {
+		vertices[i] = geom.Point{X: int64(point.Column), Y: int64(point.Row)}
 	}
+	return int(geom.InteriorPoints(vertices))
+}
 
-	column := start.Column
-	row := start.Row
-	direction := FindStartDirection(grid, start)
-
-	if direction == 0 {
-		return 0, fmt.Errorf("unable to find a direction from S")
+// EnclosedTilesByFloodFill counts the same tiles as EnclosedTiles by flood filling the outside
+func EnclosedTilesByFloodFill(grid []string, loop []Point) int {
+	// Pipes can be squeezed between, so flood fill on a grid at double the resolution,
+	// where tile (r, c) sits at (2r+1, 2c+1) and the cells in between are gaps.
+	// The extra border row/column guarantees the outside is one connected region
+	rows := 2*len(grid) + 1
+	columns := 2*len(grid[0]) + 1
+	walls := bitgrid.New(rows, columns)
+	for i, point := range loop {
+		next := loop[(i+1)%len(loop)]
+		walls.Set(2*point.Row+1, 2*point.Column+1)
+		// the gap between two connected pipes is also part of the loop
+		walls.Set(point.Row+next.Row+1, point.Column+next.Column+1)
 	}
 
-	delta := DELTA[direction]
-	row += delta.Row
-	column += delta.Column
-	loop := map[Point]struct{}{
-		*start: {},
-	}
-
-	for !(column == start.Column && row == start.Row) {
-		loop[Point{Row: row, Column: column}] = struct{}{}
-		nextDirection, err := NextDirection(grid[row][column], direction)
-		//fmt.Printf("[%d][%d] %s %s\n", row, column, string(grid[row][column]), string(direction))
-		if nextDirection == 0 {
-			return 0, fmt.Errorf(
-				"unable to find a next direction from %s at [%d][%d] going %s: %w",
-				string(grid[row][column]),
-				row,
-				column,
-				string(direction),
-				err,
-			)
-		}
-		direction = nextDirection
-		delta := DELTA[direction]
-		row += delta.Row
-		column += delta.Column
-	}
-
-	var minRow = 99999
-	var minColumn = 99999
-	var maxRow = 0
-	var maxColumn = 0
-	for point := range loop {
-		minRow = min(minRow, point.Row)
-		maxRow = max(maxRow, point.Row)
-		minColumn = min(minColumn, point.Column)
-		maxColumn = max(maxColumn, point.Column)
-	}
+	labels := lib.Label(rows, columns, lib.FillRules{
+		Passable: func(c lib.Cell) bool {
+			return !walls.Get(c.Row, c.Column)
+		},
+	})
+	outside := labels.At(lib.Cell{})
 
 	tiles := 0
-	for row := minRow; row <= maxRow; row++ {
-		for column := minColumn; column <= maxColumn; column++ {
-			point := Point{Row: row, Column: column}
-			_, partOfLoop := loop[point]
-			if partOfLoop {
-				continue
-			}
-
-			// diagonal raycasting
-			crosses := 0
-			r, c := row, column
-
-			for r <= maxRow && c <= maxColumn {
-				p := grid[r][c]
-				_, ok := loop[Point{Row: r, Column: c}]
-				if ok && p != 'L' && p != '7' {
-					crosses += 1
-				}
-				r += 1
-				c += 1
-			}
-
-			if crosses%2 == 1 {
+	for row := range grid {
+		for column := range grid[row] {
+			id := labels.At(lib.Cell{Row: 2*row + 1, Column: 2*column + 1})
+			if id != -1 && id != outside {
 				tiles += 1
 			}
 		}
 	}
 
-	return tiles, nil
+	return tiles
+}
+
+func Part2(grid []string) (int, error) {
+	loop, err := Loop(grid)
+	if err != nil {
+		return 0, err
+	}
+
+	return EnclosedTiles(loop), nil
 }
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -62,5 +65,42 @@ L7JLJL-JLJLJL--JLJ.L`, want: 10},
 		if result != testCase.want {
 			t.Errorf("expected %d, got %d", testCase.want, result)
 		}
+
+		grid := strings.Split(testCase.grid, "\n")
+		loop, err := Loop(grid)
+		if err != nil {
+			t.Error(err)
+		}
+		if result := EnclosedTilesByFloodFill(grid, loop); result != testCase.want {
+			t.Errorf("flood fill: expected %d, got %d", testCase.want, result)
+		}
+	}
+}
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestWriteLoopSVG(t *testing.T) {
+	var sb strings.Builder
+	err := WriteLoopSVG(&sb, strings.Split(`-L|F7
+7S-7|
+L|7||
+-L-J|
+L|-JF`, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "loop.svg")
+	if *update {
+		if err := os.WriteFile(golden, []byte(sb.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != string(want) {
+		t.Fatalf("SVG differs from %s, rerun with -update if the change is expected", golden)
 	}
 }
@@ -81,15 +81,12 @@ func SumOfShortestPaths(lines []string, expansionFactor int) (int, error) {
 		}
 	}
 
-	pairs := lib.Pairs(galaxies)
 	steps := 0
 
-	for _, pair := range pairs {
-		start := pair[0]
-		end := pair[1]
-
+	lib.Pairs(galaxies)(func(start, end Point) bool {
 		steps += int(math.Abs(float64(end.column-start.column)) + math.Abs(float64(end.row-start.row)))
-	}
+		return true
+	})
 
 	return steps, nil
 }
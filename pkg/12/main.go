@@ -2,13 +2,11 @@ package main
 
 import (
 	"fmt"
-	"hash/fnv"
 	"log"
 	"slices"
-	"strconv"
-	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/memo"
 )
 
 type Condition int
@@ -24,87 +22,68 @@ type Spring struct {
 }
 
 type Row struct {
-	Springs []Spring
-	Groups  []int
+	_       struct{} `aoc:"{Springs} {Groups}"`
+	Springs []Spring `sep:""`
+	Groups  []int    `sep:","`
 }
 
-type CacheKey uint64
-
-func (r *Row) Key() CacheKey {
-	hash := fnv.New64a()
-	springs := len(r.Springs)
-	groups := len(r.Groups)
-	l := springs + groups + 1
-	bytes := make([]byte, l)
-	for i, s := range r.Springs {
-		bytes[i] = byte(s.Condition)
-	}
-	// Sentinel value between springs and groups
-	bytes[springs] = byte(255)
-	for i, g := range r.Groups {
-		bytes[springs+i+1] = byte(g)
-	}
-	hash.Write(bytes)
-	return CacheKey(hash.Sum64())
+// suffix is the sub-problem left once the first `springs` springs and `groups`
+// groups of a Row have been consumed
+type suffix struct {
+	springs int
+	groups  int
 }
 
-func (r *Row) Arrangements(cache map[CacheKey]int) int {
-	key := r.Key()
-	value, ok := cache[key]
-	if ok {
-		return value
-	}
+func (r *Row) Arrangements() int {
+	arrangements := memo.Recursive(memo.New[suffix, int](), func(arrangements func(suffix) int, s suffix) int {
+		springs := r.Springs[s.springs:]
+		groups := r.Groups[s.groups:]
+
+		if len(groups) == 0 {
+			// Can only complete the row with Operational springs
+			// i.e Unknown MUST be Operational
+			// If any Damaged springs left in the row, not a valid permutation
+			if slices.ContainsFunc(springs, func(s Spring) bool {
+				return s.Condition == Damaged
+			}) {
+				return 0
+			}
+			return 1
+		}
 
-	if len(r.Groups) == 0 {
-		// Can only complete the row with Operational springs
-		// i.e Unknown MUST be Operational
-		// If any Damaged springs left in the row, not a valid permutation
-		var v int
-		if slices.ContainsFunc(r.Springs, func(s Spring) bool {
-			return s.Condition == Damaged
-		}) {
-			v = 0
-		} else {
-			v = 1
+		if len(springs) < (lib.Sum(groups) + len(groups) - 1) {
+			// We need another X Damaged (and at least 1 Operational separating them)
+			// May be impossible if we have fewer springs than needed
+			return 0
 		}
-		cache[key] = v
-		return v
-	}
 
-	if len(r.Springs) < (lib.Sum(r.Groups) + len(r.Groups) - 1) {
-		// We need another X Damaged (and at least 1 Operational separating them)
-		// May be impossible if we have fewer springs than needed
-		cache[key] = 0
-		return 0
-	}
+		if springs[0].Condition == Operational {
+			return arrangements(suffix{springs: s.springs + 1, groups: s.groups})
+		}
 
-	if r.Springs[0].Condition == Operational {
-		arrangements := (&Row{Springs: r.Springs[1:], Groups: slices.Clone(r.Groups)}).Arrangements(cache)
-		cache[key] = arrangements
-		return arrangements
-	}
+		var total int
+		// Can we consume a damaged group from current position (using a known Damaged or Unknown spring)
+		group := groups[0]
+		allNonOperational := !slices.ContainsFunc(springs[:group], func(s Spring) bool {
+			return s.Condition == Operational
+		})
+		end := lib.Min(group+1, len(springs))
+		if allNonOperational && (
+		// Does Damaged block consume all remaining springs
+		// Is the first spring AFTER this Damaged block Operational OR Unknown
+		(len(springs) > group && springs[group].Condition != Damaged) || len(springs) <= group) {
+			total = arrangements(suffix{springs: s.springs + end, groups: s.groups + 1})
+		}
 
-	var arrangements int
-	// Can we consume a damaged group from current position (using a known Damaged or Unknown spring)
-	group := r.Groups[0]
-	allNonOperational := !slices.ContainsFunc(r.Springs[:group], func(s Spring) bool {
-		return s.Condition == Operational
-	})
-	end := lib.Min(group+1, len(r.Springs))
-	if allNonOperational && (
-	// Does Damaged block consume all remaining springs
-	// Is the first spring AFTER this Damaged block Operational OR Unknown
-	(len(r.Springs) > group && r.Springs[group].Condition != Damaged) || len(r.Springs) <= group) {
-		arrangements = (&Row{Springs: r.Springs[end:], Groups: r.Groups[1:]}).Arrangements(cache)
-	}
+		// Use Unknown as an Operational
+		if springs[0].Condition == Unknown {
+			total += arrangements(suffix{springs: s.springs + 1, groups: s.groups})
+		}
 
-	// Use Unknown as an Operational
-	if r.Springs[0].Condition == Unknown {
-		arrangements += (&Row{Springs: r.Springs[1:], Groups: slices.Clone(r.Groups)}).Arrangements(cache)
-	}
+		return total
+	})
 
-	cache[key] = arrangements
-	return arrangements
+	return arrangements(suffix{})
 }
 
 func (r *Row) Unfold() {
@@ -136,33 +115,24 @@ func ParseSpring(condition rune) (Spring, error) {
 	}
 }
 
-func ParseRow(row string) (*Row, error) {
-	parts := strings.Split(row, " ")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("expected condition record and groups of damaged springs space separated")
+func (s *Spring) UnmarshalText(text []byte) error {
+	if len(text) != 1 {
+		return fmt.Errorf("expected a single condition, got %q", text)
 	}
-	conditionRecord := parts[0]
-
-	springs := make([]Spring, len(conditionRecord))
-	for i, r := range conditionRecord {
-		spring, err := ParseSpring(r)
-		if err != nil {
-			return nil, fmt.Errorf("invalid spring at index %d: %w", i, err)
-		}
-		springs[i] = spring
+	spring, err := ParseSpring(rune(text[0]))
+	if err != nil {
+		return err
 	}
+	*s = spring
+	return nil
+}
 
-	groupsOfDamagedSprings := strings.Split(parts[1], ",")
-	groups := make([]int, len(groupsOfDamagedSprings))
-	for i, g := range groupsOfDamagedSprings {
-		n, err := strconv.Atoi(g)
-		if err != nil {
-			return nil, fmt.Errorf("invalid group at index %d, %w", i, err)
-		}
-		groups[i] = n
+func ParseRow(row string) (*Row, error) {
+	var r Row
+	if err := lib.Unmarshal(row, &r); err != nil {
+		return nil, fmt.Errorf("expected condition record and groups of damaged springs space separated: %w", err)
 	}
-
-	return &Row{Springs: springs, Groups: groups}, nil
+	return &r, nil
 }
 
 func main() {
@@ -186,14 +156,13 @@ func main() {
 
 func Part1(lines []string) (int, error) {
 	arrangements := 0
-	cache := make(map[CacheKey]int)
 
 	for i, line := range lines {
 		row, err := ParseRow(line)
 		if err != nil {
 			return 0, fmt.Errorf("line %d: %w", i, err)
 		}
-		arrangements += row.Arrangements(cache)
+		arrangements += row.Arrangements()
 	}
 
 	return arrangements, nil
@@ -201,7 +170,6 @@ func Part1(lines []string) (int, error) {
 
 func Part2(lines []string) (int, error) {
 	var arrangements int
-	cache := make(map[CacheKey]int)
 
 	for i, line := range lines {
 		row, err := ParseRow(line)
@@ -209,7 +177,7 @@ func Part2(lines []string) (int, error) {
 			return 0, fmt.Errorf("line %d: %w", i, err)
 		}
 		row.Unfold()
-		arrangements += row.Arrangements(cache)
+		arrangements += row.Arrangements()
 	}
 
 	return arrangements, nil
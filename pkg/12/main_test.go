@@ -51,12 +51,11 @@ func TestArrangements(t *testing.T) {
 		},
 	}
 	for _, testCase := range testCases {
-		cache := make(map[CacheKey]int)
 		row, err := ParseRow(testCase.row)
 		if err != nil {
 			t.Error(err)
 		}
-		if got := row.Arrangements(cache); got != testCase.want {
+		if got := row.Arrangements(); got != testCase.want {
 			t.Errorf("expected %d, got %d", testCase.want, got)
 		}
 	}
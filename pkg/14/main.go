@@ -1,12 +1,16 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"hash/fnv"
+	"image/color"
+	"io"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/bitgrid"
 )
 
 type Direction int
@@ -41,118 +45,86 @@ func ParseSpace(c rune) (Space, error) {
 	}
 }
 
-type Platform [][]Space
+// Platform tracks rounded and cube-shaped rocks as bit grids; any other space is Empty
+type Platform struct {
+	rounded *bitgrid.Grid
+	cubes   *bitgrid.Grid
+	// animation, if set, gets a frame after every Tilt
+	animation *lib.Animation
+}
+
+// Animate records a frame to animation after every subsequent Tilt
+func (p *Platform) Animate(animation *lib.Animation) {
+	p.animation = animation
+}
+
+func (p *Platform) Rows() int {
+	return p.rounded.Rows()
+}
+
+func (p *Platform) Columns() int {
+	return p.rounded.Columns()
+}
+
+func (p *Platform) Space(row, column int) Space {
+	if p.rounded.Get(row, column) {
+		return RoundedRock
+	}
+	if p.cubes.Get(row, column) {
+		return CubeShapedRock
+	}
+	return Empty
+}
+
+// roll moves every rounded rock along one line of the platform as far as it will go.
+// at maps a position along the line to a (row, column), starting from the edge the
+// rocks roll towards
+func (p *Platform) roll(length int, at func(i int) (int, int)) {
+	// the next position a rounded rock would come to rest at
+	free := 0
+	for i := 0; i < length; i++ {
+		row, column := at(i)
+		if p.cubes.Get(row, column) {
+			free = i + 1
+			continue
+		}
+		if !p.rounded.Get(row, column) {
+			continue
+		}
+		if free != i {
+			p.rounded.Clear(row, column)
+			p.rounded.Set(at(free))
+		}
+		free++
+	}
+}
 
 func (p *Platform) Tilt(direction Direction) {
-	platform := *p
-	columns := len(platform[0])
-	rows := len(platform)
+	rows := p.Rows()
+	columns := p.Columns()
 
-	if direction == North {
+	switch direction {
+	case North:
 		for column := 0; column < columns; column++ {
-			availableSpaces := 0
-			for row := 0; row < rows; row++ {
-				current := platform[row][column]
-				if current == Empty {
-					availableSpaces += 1
-					continue
-				}
-
-				if current == CubeShapedRock {
-					availableSpaces = 0
-					continue
-				}
-
-				// RoundedRock
-				if availableSpaces > 0 {
-					platform[row][column] = Empty
-					platform[row-availableSpaces][column] = RoundedRock
-					// availableSpaces remains the same, as we have "swapped" the current space for
-					// a space above
-				} else {
-					availableSpaces = 0
-				}
-			}
+			p.roll(rows, func(i int) (int, int) { return i, column })
 		}
-	} else if direction == South {
+	case South:
 		for column := 0; column < columns; column++ {
-			availableSpaces := 0
-			for row := rows - 1; row >= 0; row-- {
-				current := platform[row][column]
-				if current == Empty {
-					availableSpaces += 1
-					continue
-				}
-
-				if current == CubeShapedRock {
-					availableSpaces = 0
-					continue
-				}
-
-				// RoundedRock
-				if availableSpaces > 0 {
-					platform[row][column] = Empty
-					platform[row+availableSpaces][column] = RoundedRock
-					// availableSpaces remains the same, as we have "swapped" the current space for
-					// a space above
-				} else {
-					availableSpaces = 0
-				}
-			}
+			p.roll(rows, func(i int) (int, int) { return rows - 1 - i, column })
 		}
-	} else if direction == West {
+	case West:
 		for row := 0; row < rows; row++ {
-			availableSpaces := 0
-			for column := 0; column < columns; column++ {
-				current := platform[row][column]
-				if current == Empty {
-					availableSpaces += 1
-					continue
-				}
-
-				if current == CubeShapedRock {
-					availableSpaces = 0
-					continue
-				}
-
-				// RoundedRock
-				if availableSpaces > 0 {
-					platform[row][column] = Empty
-					platform[row][column-availableSpaces] = RoundedRock
-					// availableSpaces remains the same, as we have "swapped" the current space for
-					// a space above
-				} else {
-					availableSpaces = 0
-				}
-			}
+			p.roll(columns, func(i int) (int, int) { return row, i })
 		}
-	} else if direction == East {
+	case East:
 		for row := 0; row < rows; row++ {
-			availableSpaces := 0
-			for column := columns - 1; column >= 0; column-- {
-				current := platform[row][column]
-				if current == Empty {
-					availableSpaces += 1
-					continue
-				}
-
-				if current == CubeShapedRock {
-					availableSpaces = 0
-					continue
-				}
-
-				// RoundedRock
-				if availableSpaces > 0 {
-					platform[row][column] = Empty
-					platform[row][column+availableSpaces] = RoundedRock
-					// availableSpaces remains the same, as we have "swapped" the current space for
-					// a space above
-				} else {
-					availableSpaces = 0
-				}
-			}
+			p.roll(columns, func(i int) (int, int) { return row, columns - 1 - i })
 		}
 	}
+
+	if p.animation != nil {
+		p.animation.AddFrame(p)
+	}
 }
 
 func (p *Platform) Cycle() {
@@ -162,49 +134,43 @@ func (p *Platform) Cycle() {
 }
 
 func (p *Platform) Load() int {
-	platform := *p
-	rows := len(platform)
+	rows := p.Rows()
 	load := 0
 
-	for i, row := range platform {
-		for _, space := range row {
-			if space != RoundedRock {
-				continue
-			}
-
-			load += (rows - i)
-		}
+	for row := 0; row < rows; row++ {
+		load += p.rounded.CountRow(row) * (rows - row)
 	}
 
 	return load
 }
 
+// Hash only needs the rounded rocks, since the cube-shaped rocks never move
 func (p *Platform) Hash() uint64 {
-	hash := fnv.New64a()
-	platform := *p
-	for _, row := range platform {
-		for _, space := range row {
-			hash.Write([]byte{byte(space)})
-		}
+	return p.rounded.Hash()
+}
+
+func (p *Platform) Rune(row, column int) rune {
+	switch p.Space(row, column) {
+	case RoundedRock:
+		return 'O'
+	case CubeShapedRock:
+		return '#'
+	default:
+		return '.'
 	}
-	return hash.Sum64()
+}
+
+var roundedRockStyle = lib.Style{Foreground: color.RGBA{R: 0xff, G: 0xc0, B: 0x40, A: 0xff}, Bold: true}
+
+// Render draws the platform to w, colouring the rounded rocks when w is a terminal
+func (p *Platform) Render(w io.Writer) error {
+	return lib.NewRenderer(w).Render(p, lib.Highlight(p.rounded.Get, roundedRockStyle))
 }
 
 func (p *Platform) Debug() string {
-	platform := *p
 	var sb strings.Builder
-	for _, row := range platform {
-		for _, space := range row {
-			switch space {
-			case RoundedRock:
-				sb.WriteRune('O')
-			case CubeShapedRock:
-				sb.WriteRune('#')
-			case Empty:
-				sb.WriteRune('.')
-			}
-		}
-		sb.WriteRune('\n')
+	if err := p.Render(&sb); err != nil {
+		return fmt.Sprintf("failed to render platform: %v", err)
 	}
 	return sb.String()
 }
@@ -212,38 +178,92 @@ func (p *Platform) Debug() string {
 func ParsePlatform(lines []string) (*Platform, error) {
 	columns := len(lines[0])
 	rows := len(lines)
-	platform := make(Platform, rows)
+	platform := Platform{rounded: bitgrid.New(rows, columns), cubes: bitgrid.New(rows, columns)}
 	for i, line := range lines {
-		spaces := make([]Space, columns)
 		for j, c := range line {
 			space, err := ParseSpace(c)
 			if err != nil {
 				return &Platform{}, fmt.Errorf("invalid space line %d position %d: %w", i, j, err)
 			}
-			spaces[j] = space
+			switch space {
+			case RoundedRock:
+				platform.rounded.Set(i, j)
+			case CubeShapedRock:
+				platform.cubes.Set(i, j)
+			}
 		}
-		platform[i] = spaces
 	}
 	return &platform, nil
 }
 
+var imageOptions = lib.ImageOptions{
+	CellSize: 4,
+	Palette: map[rune]color.Color{
+		'O': roundedRockStyle.Foreground,
+		'#': color.RGBA{R: 0x60, G: 0x60, B: 0x70, A: 0xff},
+	},
+	Background: color.RGBA{R: 0x10, G: 0x10, B: 0x18, A: 0xff},
+}
+
+// WriteCycleGIF writes an animation of every Tilt in the first n cycles to path
+func WriteCycleGIF(lines []string, path string, n int) error {
+	platform, err := ParsePlatform(lines)
+	if err != nil {
+		return fmt.Errorf("failed to parse platform: %w", err)
+	}
+
+	animation := lib.NewAnimation(imageOptions, 25)
+	animation.AddFrame(platform)
+	platform.Animate(animation)
+	for i := 0; i < n; i++ {
+		platform.Cycle()
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file at %s: %w", path, err)
+	}
+	if err := animation.WriteGIF(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write GIF to %s: %w", path, err)
+	}
+	return f.Close()
+}
+
 func main() {
+	gifPath := flag.String("gif", "", "write an animated GIF of the first cycles to this path")
+	cycles := flag.Int("cycles", 3, "the number of cycles to animate with --gif")
+	render := flag.Bool("render", false, "draw the platform after part 2's cycles")
+	flag.Parse()
+
 	lines, err := lib.ReadLines("pkg/14/input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if *gifPath != "" {
+		if err := WriteCycleGIF(lines, *gifPath, *cycles); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	part1, err := Part1(lines)
 	if err != nil {
 		log.Fatal(err)
 	}
 	fmt.Printf("part1: %d\n", part1)
 
-	part2, err := Part2(lines)
+	platform, err := SpinCycle(lines)
 	if err != nil {
 		log.Fatal(err)
 	}
-	fmt.Printf("part2: %d\n", part2)
+	fmt.Printf("part2: %d\n", platform.Load())
+
+	if *render {
+		if err := platform.Render(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
 func Part1(lines []string) (int, error) {
@@ -258,9 +278,19 @@ func Part1(lines []string) (int, error) {
 }
 
 func Part2(lines []string) (int, error) {
+	platform, err := SpinCycle(lines)
+	if err != nil {
+		return 0, err
+	}
+	return platform.Load(), nil
+}
+
+// SpinCycle returns the platform after a billion cycles, skipping ahead once the
+// platform repeats
+func SpinCycle(lines []string) (*Platform, error) {
 	platform, err := ParsePlatform(lines)
 	if err != nil {
-		return 0, fmt.Errorf("failed to parse platform: %w", err)
+		return nil, fmt.Errorf("failed to parse platform: %w", err)
 	}
 
 	cache := map[uint64]int{
@@ -278,12 +308,10 @@ func Part2(lines []string) (int, error) {
 			for j := 0; j < remainder; j++ {
 				platform.Cycle()
 			}
-			fmt.Println(platform.Debug())
-			return platform.Load(), nil
+			return platform, nil
 		}
 		cache[hash] = i + 1
 	}
 
-	fmt.Println(platform.Debug())
-	return platform.Load(), nil
+	return platform, nil
 }
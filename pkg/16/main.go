@@ -1,19 +1,73 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"image/color"
+	"io"
 	"log"
-	"strings"
+	"os"
 
 	"github.com/max-nicholson/advent-of-code-2023/lib"
+	"github.com/max-nicholson/advent-of-code-2023/lib/bitgrid"
 )
 
+var imageOptions = lib.ImageOptions{
+	CellSize: 4,
+	Palette: map[rune]color.Color{
+		'/':  color.RGBA{R: 0x90, G: 0x90, B: 0xa0, A: 0xff},
+		'\\': color.RGBA{R: 0x90, G: 0x90, B: 0xa0, A: 0xff},
+		'|':  color.RGBA{R: 0x60, G: 0x60, B: 0x70, A: 0xff},
+		'-':  color.RGBA{R: 0x60, G: 0x60, B: 0x70, A: 0xff},
+	},
+	Background: color.RGBA{R: 0x10, G: 0x10, B: 0x18, A: 0xff},
+}
+
+// WriteBeamGIF writes an animation of the beam from start spreading through the
+// contraption to path, adding a frame every `every` steps, or every step if every is below 1
+func WriteBeamGIF(lines []string, start *Visit, path string, every int) error {
+	every = max(every, 1)
+	animation := lib.NewAnimation(imageOptions, 4)
+	steps := 0
+	energized, err := TraceEnergized(lines, start, func(visited *bitgrid.LayeredGrid) {
+		if steps%every == 0 {
+			animation.AddFrame(lib.Lines(lines), lib.Highlight(visited.Any, energizedStyle))
+		}
+		steps++
+	})
+	if err != nil {
+		return fmt.Errorf("failed to calculate energized tiles: %w", err)
+	}
+	// always finish on the fully energized contraption
+	animation.AddFrame(lib.Lines(lines), lib.Highlight(energized.Get, energizedStyle))
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file at %s: %w", path, err)
+	}
+	if err := animation.WriteGIF(f); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write GIF to %s: %w", path, err)
+	}
+	return f.Close()
+}
+
 func main() {
+	gifPath := flag.String("gif", "", "write an animated GIF of the beam for part 1 to this path")
+	every := flag.Int("every", 50, "the number of beam steps per frame with --gif")
+	flag.Parse()
+
 	lines, err := lib.ReadLines("pkg/16/input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	if *gifPath != "" {
+		if err := WriteBeamGIF(lines, &Visit{Point{0, 0}, 'E'}, *gifPath, *every); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	part1, err := Part1(lines)
 	if err != nil {
 		log.Fatal(err)
@@ -39,39 +93,42 @@ var DELTA = map[byte]*Point{
 	'E': {column: 1},
 }
 
+var layers = map[byte]bitgrid.Direction{
+	'N': bitgrid.North,
+	'S': bitgrid.South,
+	'W': bitgrid.West,
+	'E': bitgrid.East,
+}
+
 type Visit struct {
 	Point
 	direction byte
 }
 
-func PrintEnergized(energized map[Point]struct{}, rows int, columns int) {
-	printLines := make([]string, rows)
-	for row := 0; row < rows; row++ {
-		var sb strings.Builder
-		for column := 0; column < columns; column++ {
-			_, ok := energized[Point{row, column}]
-			if ok {
-				sb.WriteByte('#')
-			} else {
-				sb.WriteByte('.')
-			}
-		}
-		printLines[row] = sb.String()
-	}
-	fmt.Println(strings.Join(printLines, "\n"))
+var energizedStyle = lib.Style{Rune: '#', Foreground: color.RGBA{R: 0xff, G: 0xe0, B: 0x30, A: 0xff}, Bold: true}
+
+// PrintEnergized draws the contraption to w with every energized tile marked
+func PrintEnergized(w io.Writer, lines []string, energized *bitgrid.Grid) error {
+	return lib.NewRenderer(w).Render(lib.Lines(lines), lib.Highlight(energized.Get, energizedStyle))
+}
+
+func CalculateEnergized(lines []string, start *Visit) (*bitgrid.Grid, error) {
+	return TraceEnergized(lines, start, nil)
 }
 
-func CalculateEnergized(lines []string, start *Visit) (map[Point]struct{}, error) {
-	energized := map[Point]struct{}{}
-	visited := map[Visit]struct{}{}
+// TraceEnergized is CalculateEnergized, calling step (if not nil) each time the beam
+// passes through a tile in a new direction
+func TraceEnergized(lines []string, start *Visit, step func(visited *bitgrid.LayeredGrid)) (*bitgrid.Grid, error) {
+	rows := len(lines)
+	columns := len(lines[0])
+
+	// A tile is energized once a beam has passed through it in any direction
+	visited := bitgrid.NewLayered(rows, columns)
 
 	stack := []Visit{
 		*start,
 	}
 
-	rows := len(lines)
-	columns := len(lines[0])
-
 	for len(stack) > 0 {
 		visit := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
@@ -80,14 +137,14 @@ func CalculateEnergized(lines []string, start *Visit) (map[Point]struct{}, error
 			continue
 		}
 
-		_, ok := visited[visit]
-		if ok {
+		if !visited.Add(point.row, point.column, layers[visit.direction]) {
 			continue
 		}
+		if step != nil {
+			step(visited)
+		}
 
 		value := lines[point.row][point.column]
-		energized[point] = struct{}{}
-		visited[visit] = struct{}{}
 
 		if value == '.' {
 			delta := DELTA[visit.direction]
@@ -168,10 +225,10 @@ func CalculateEnergized(lines []string, start *Visit) (map[Point]struct{}, error
 				)
 			}
 		} else {
-			return energized, fmt.Errorf("unexpected value %s at (%d, %d)", string(value), point.row, point.column)
+			return visited.Flatten(), fmt.Errorf("unexpected value %s at (%d, %d)", string(value), point.row, point.column)
 		}
 	}
-	return energized, nil
+	return visited.Flatten(), nil
 }
 
 func Part1(lines []string) (int, error) {
@@ -180,7 +237,7 @@ func Part1(lines []string) (int, error) {
 		return 0, fmt.Errorf("failed to calculate energized tiles: %w", err)
 	}
 
-	return len(energized), nil
+	return energized.Count(), nil
 }
 
 func Part2(lines []string) (int, error) {
@@ -201,7 +258,7 @@ func Part2(lines []string) (int, error) {
 			if err != nil {
 				return 0, fmt.Errorf("failed to calculate energized tiles for (%d, %d): %w", edge.row, column, err)
 			}
-			maxEnergized = max(maxEnergized, len(energized))
+			maxEnergized = max(maxEnergized, energized.Count())
 		}
 	}
 
@@ -218,7 +275,7 @@ func Part2(lines []string) (int, error) {
 			if err != nil {
 				return 0, fmt.Errorf("failed to calculate energized tiles for (%d, %d): %w", row, edge.column, err)
 			}
-			maxEnergized = max(maxEnergized, len(energized))
+			maxEnergized = max(maxEnergized, energized.Count())
 		}
 	}
 
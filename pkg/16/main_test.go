@@ -1,6 +1,9 @@
 package main
 
 import (
+	"image/gif"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -42,3 +45,38 @@ func TestPart2(t *testing.T) {
 		t.Fatalf("expected 51, got %d", result)
 	}
 }
+
+func TestWriteBeamGIF(t *testing.T) {
+	lines := strings.Split(`.|...\....
+|.-.\.....
+.....|-...
+........|.
+..........
+.........\
+..../.\\..
+.-.-/..|..
+.|....-|.\
+..//.|....`, "\n")
+	path := filepath.Join(t.TempDir(), "beam.gif")
+	if err := WriteBeamGIF(lines, &Visit{Point{0, 0}, 'E'}, path, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	animation, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(animation.Image) < 2 {
+		t.Fatalf("expected the beam to spread over several frames, got %d", len(animation.Image))
+	}
+
+	// every below 1 falls back to a frame per step rather than dividing by zero
+	if err := WriteBeamGIF(lines, &Visit{Point{0, 0}, 'E'}, filepath.Join(t.TempDir(), "every.gif"), 0); err != nil {
+		t.Fatal(err)
+	}
+}
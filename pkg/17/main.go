@@ -2,8 +2,13 @@ package main
 
 import (
 	"container/heap"
+	"flag"
 	"fmt"
+	"image/color"
+	"io"
 	"log"
+	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -68,6 +73,9 @@ func (pq *PriorityQueue) Pop() any {
 }
 
 func main() {
+	svgPath := flag.String("svg", "", "write the part 1 route over a heatmap of the city as an SVG to this path")
+	flag.Parse()
+
 	lines, err := lib.ReadLines("pkg/17/input.txt")
 	if err != nil {
 		log.Fatal(err)
@@ -81,6 +89,20 @@ func main() {
 		log.Fatal(err)
 	}
 	fmt.Printf("part2: %d\n", part2)
+
+	if *svgPath != "" {
+		f, err := os.Create(*svgPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := WriteRouteSVG(f, lines, 0, 3); err != nil {
+			f.Close()
+			log.Fatal(err)
+		}
+		if err := f.Close(); err != nil {
+			log.Fatal(err)
+		}
+	}
 }
 
 func ParseLines(lines []string) [][]int {
@@ -100,15 +122,36 @@ func ParseLines(lines []string) [][]int {
 
 func Part1(lines []string) int {
 	grid := ParseLines(lines)
-	return dijkstra(grid, 0, 3)
+	cost, _ := dijkstra(grid, 0, 3)
+	return cost
 }
 
 func Part2(lines []string) int {
 	grid := ParseLines(lines)
-	return dijkstra(grid, 4, 10)
+	cost, _ := dijkstra(grid, 4, 10)
+	return cost
 }
 
-func dijkstra(grid [][]int, minStraight int, maxStraight int) int {
+// Route returns the cheapest path for the crucible from the top left to the bottom right,
+// including both ends, and its heat loss
+func Route(grid [][]int, minStraight int, maxStraight int) (int, []lib.Cell) {
+	return dijkstra(grid, minStraight, maxStraight)
+}
+
+// WriteRouteSVG writes the heat loss of each block with the cheapest route over it to w
+func WriteRouteSVG(w io.Writer, lines []string, minStraight int, maxStraight int) error {
+	grid := ParseLines(lines)
+	_, route := Route(grid, minStraight, maxStraight)
+	return lib.WriteSVG(w, grid, route, lib.SVGOptions{
+		CellSize: 12,
+		Low:      color.RGBA{R: 0xff, G: 0xf3, B: 0xc4, A: 0xff},
+		High:     color.RGBA{R: 0xb0, G: 0x20, B: 0x10, A: 0xff},
+		Stroke:   color.RGBA{R: 0x10, G: 0x30, B: 0xc0, A: 0xff},
+		Labels:   true,
+	})
+}
+
+func dijkstra(grid [][]int, minStraight int, maxStraight int) (int, []lib.Cell) {
 	rows := len(grid)
 	columns := len(grid[0])
 
@@ -121,6 +164,7 @@ func dijkstra(grid [][]int, minStraight int, maxStraight int) int {
 	}
 
 	minCost := map[state]int{startRight: 0, startDown: 0}
+	previous := map[state]state{}
 	heap.Init(&pq)
 
 	for len(pq) > 0 {
@@ -134,7 +178,12 @@ func dijkstra(grid [][]int, minStraight int, maxStraight int) int {
 
 		if curr.state.row == rows-1 && curr.state.column == columns-1 && curr.state.acc >= minStraight {
 			// End state
-			return curr.cost
+			path := []lib.Cell{}
+			for s, ok := curr.state, true; ok; s, ok = previous[s] {
+				path = append(path, lib.Cell{Row: s.row, Column: s.column})
+			}
+			slices.Reverse(path)
+			return curr.cost, path
 		}
 
 		currentDirection := curr.state.direction
@@ -180,9 +229,10 @@ func dijkstra(grid [][]int, minStraight int, maxStraight int) int {
 			}
 
 			minCost[nextState] = nextCost
+			previous[nextState] = curr.state
 			heap.Push(&pq, &item{cost: nextCost, state: nextState})
 		}
 	}
 
-	return 0
+	return 0, nil
 }
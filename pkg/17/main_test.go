@@ -1,8 +1,13 @@
 package main
 
 import (
+	"flag"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/max-nicholson/advent-of-code-2023/lib"
 )
 
 func TestPart1(t *testing.T) {
@@ -42,3 +47,55 @@ func TestPart2(t *testing.T) {
 		t.Fatalf("expected 94, got %d", result)
 	}
 }
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+var example = strings.Split(`2413432311323
+3215453535623
+3255245654254
+3446585845452
+4546657867536
+1438598798454
+4457876987766
+3637877979653
+4654967986887
+4564679986453
+1224686865563
+2546548887735
+4322674655533`, "\n")
+
+func TestRoute(t *testing.T) {
+	grid := ParseLines(example)
+	cost, route := Route(grid, 0, 3)
+	if route[0] != (lib.Cell{Row: 0, Column: 0}) || route[len(route)-1] != (lib.Cell{Row: 12, Column: 12}) {
+		t.Fatalf("expected route from the top left to the bottom right, got %v", route)
+	}
+	total := 0
+	for _, cell := range route[1:] {
+		total += grid[cell.Row][cell.Column]
+	}
+	if total != cost {
+		t.Fatalf("expected route to lose %d heat, got %d", cost, total)
+	}
+}
+
+func TestWriteRouteSVG(t *testing.T) {
+	var sb strings.Builder
+	if err := WriteRouteSVG(&sb, example, 0, 3); err != nil {
+		t.Fatal(err)
+	}
+
+	golden := filepath.Join("testdata", "route.svg")
+	if *update {
+		if err := os.WriteFile(golden, []byte(sb.String()), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sb.String() != string(want) {
+		t.Fatalf("SVG differs from %s, rerun with -update if the change is expected", golden)
+	}
+}